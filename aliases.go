@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// aliasMapping is one row of the --aliases table: an import prefix such as
+// @app and the directories it points at.
+type aliasMapping struct {
+	alias string
+	dirs  []string
+}
+
+var aliasMappings []aliasMapping
+
+// loadAliasTable reads a JSON object mapping directory globs, relative to the
+// file itself, to import-alias prefixes:
+//
+//	{"src/components/ui": "@ui", "src/components/app": "@app"}
+func loadAliasTable(path string) ([]aliasMapping, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading alias table: %v", err)
+	}
+	table, err := decodeStringTable(content)
+	if err != nil {
+		return nil, fmt.Errorf("malformed alias table %s: %v", path, err)
+	}
+
+	base := filepath.Dir(path)
+	patterns := make([]string, 0, len(table))
+	for pattern := range table {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	var mappings []aliasMapping
+	for _, pattern := range patterns {
+		alias := strings.TrimSuffix(table[pattern], "/")
+		if alias == "" {
+			return nil, fmt.Errorf("alias table %s: empty alias for %q", path, pattern)
+		}
+		matches, err := filepath.Glob(filepath.Join(base, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, fmt.Errorf("alias table %s: %v", path, err)
+		}
+		mapping := aliasMapping{alias: alias}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err != nil || !info.IsDir() {
+				continue
+			}
+			abs, err := filepath.Abs(match)
+			if err != nil {
+				return nil, err
+			}
+			mapping.dirs = append(mapping.dirs, abs)
+		}
+		if len(mapping.dirs) == 0 {
+			return nil, fmt.Errorf("alias table %s: %q matches no directory", path, pattern)
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings, nil
+}
+
+// rebaseAliasDirs points alias directories inside src at their copies in dst,
+// for --output-dir.
+func rebaseAliasDirs(src, dst string) error {
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+	absDst, err := filepath.Abs(dst)
+	if err != nil {
+		return err
+	}
+	for i := range aliasMappings {
+		for j, dir := range aliasMappings[i].dirs {
+			if rel, err := filepath.Rel(absSrc, dir); err == nil && isWithin(dir, absSrc) {
+				aliasMappings[i].dirs[j] = filepath.Join(absDst, rel)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveAliasedSpecifier resolves a specifier that starts with a mapped alias
+// against the component index, from the directory the alias points at. It
+// reports false when no mapping covers the specifier, so the name-based
+// patterns are used instead.
+func resolveAliasedSpecifier(specifier string) (string, bool) {
+	for _, mapping := range aliasMappings {
+		rest, ok := strings.CutPrefix(specifier, mapping.alias+"/")
+		if !ok {
+			continue
+		}
+		for _, dir := range mapping.dirs {
+			if !isWithin(dir, componentRoot) {
+				continue
+			}
+			if resolved := resolveSegments(dir, rest); resolved != rest {
+				return mapping.alias + "/" + resolved, true
+			}
+		}
+		for _, dir := range mapping.dirs {
+			if isWithin(dir, componentRoot) {
+				return specifier, true
+			}
+		}
+	}
+	return specifier, false
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAliasTable(t *testing.T) {
+	files := map[string]string{
+		"aliases.json":                     `{"components/ui": "@ui", "components/app": "@app/"}`,
+		"components/ui/Button/Button.vue":  `export default {}`,
+		"components/app/Dialog/Dialog.vue": `export default {}`,
+		"components/Home.vue": `import Button from '@ui/Button/Button.vue'
+import Dialog from '@app/Dialog/Dialog.vue'
+import Missing from '@app/Button/Button.vue'`,
+	}
+	tmpDir := writeTree(t, files)
+
+	var err error
+	aliasMappings, err = loadAliasTable(filepath.Join(tmpDir, "aliases.json"))
+	if err != nil {
+		t.Fatalf("loadAliasTable failed: %v", err)
+	}
+	defer func() { aliasMappings = nil }()
+	if len(aliasMappings) != 2 || aliasMappings[0].alias != "@app" || aliasMappings[1].alias != "@ui" {
+		t.Fatalf("aliasMappings = %v; want @app and @ui", aliasMappings)
+	}
+
+	root := filepath.Join(tmpDir, "components")
+	globalRenames = map[string]string{"Button": "button", "Dialog": "dialog"}
+	defer func() {
+		globalRenames = make(map[string]string)
+		componentIndex = nil
+	}()
+	if err := indexComponents(root); err != nil {
+		t.Fatalf("indexComponents failed: %v", err)
+	}
+	if err := processFiles(root); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	// @app has no Button folder, so that import is left for the user to fix.
+	expected := `import Button from '@ui/button/button.vue'
+import Dialog from '@app/dialog/dialog.vue'
+import Missing from '@app/Button/Button.vue'`
+	content, err := os.ReadFile(filepath.Join(root, "Home.vue"))
+	if err != nil {
+		t.Fatalf("Failed to read Home.vue: %v", err)
+	}
+	if string(content) != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, content)
+	}
+}
+
+func TestAliasTableUnmatchedGlob(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	path := filepath.Join(tmpDir, "aliases.json")
+	if err := os.WriteFile(path, []byte(`{"src/missing/*": "@missing"}`), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if _, err := loadAliasTable(path); err == nil {
+		t.Error("loadAliasTable succeeded for a glob that matches no directory")
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// loadAllowList reads --allow-file: component names approved for renaming,
+// one per line, with blank lines and # comments ignored.
+func loadAllowList(path string) (map[string]bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading allow list: %v", err)
+	}
+	return parseComponentList(string(content)), nil
+}
+
+// restrictToAllowList drops every rename whose component is not approved,
+// however it was found.
+func restrictToAllowList(allowed map[string]bool) {
+	for _, name := range sortedRenameNames() {
+		if !allowed[name] {
+			delete(globalRenames, name)
+			fmt.Fprintf(stdout, "Skipping component not in allow list: %s\n", name)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAllowList(t *testing.T) {
+	previous := stdout
+	stdout = io.Discard
+	defer func() { stdout = previous }()
+	defer func() { globalRenames = make(map[string]string) }()
+
+	files := map[string]string{
+		"Button.vue": `import Slider from './Slider.vue'
+export default {}`,
+		"Slider.vue": `import Button from './Button.vue'
+export default {}`,
+		"allowed.txt": `# Approved by the platform team
+Button
+
+DialogContent
+`,
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = make(map[string]string)
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if _, ok := globalRenames["Slider"]; !ok {
+		t.Fatalf("Slider was not detected: %v", globalRenames)
+	}
+
+	allowed, err := loadAllowList(filepath.Join(tmpDir, "allowed.txt"))
+	if err != nil {
+		t.Fatalf("loadAllowList failed: %v", err)
+	}
+	restrictToAllowList(allowed)
+
+	if len(globalRenames) != 1 || globalRenames["Button"] != "button" {
+		t.Fatalf("globalRenames = %v; want only Button", globalRenames)
+	}
+
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "Slider.vue")); err != nil {
+		t.Errorf("Slider.vue should not have been renamed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(tmpDir, "Slider.vue"))
+	if err != nil {
+		t.Fatalf("Failed to read Slider.vue: %v", err)
+	}
+	if string(content) != "import Button from './button.vue'\nexport default {}" {
+		t.Errorf("import of allowed component should be rewritten:\n%s", content)
+	}
+
+	if _, err := loadAllowList(filepath.Join(tmpDir, "missing.txt")); err == nil {
+		t.Errorf("loadAllowList succeeded on a missing file")
+	}
+}
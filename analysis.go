@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// rewriteExtensions are the files whose imports are rewritten. They are set
+// apart from componentExtensions, the files that are renamed, so .ts files
+// can be edited without ever being renamed; --rewrite-ext and --rename-ext
+// replace each set.
+var rewriteExtensions = []string{".vue", ".ts", ".astro"}
+
+func isSourceFile(name string) bool {
+	// .ts covers .d.ts declaration files too.
+	if slices.Contains(rewriteExtensions, filepath.Ext(name)) {
+		return true
+	}
+	if opts.markdown && isMarkdownFile(name) {
+		return true
+	}
+	if isScriptSibling(name) {
+		return true
+	}
+	return false
+}
+
+// plannedPath returns where path will live once processFiles has applied the
+// rename map to the tree rooted at root.
+func plannedPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return path
+	}
+
+	parts := strings.Split(rel, string(filepath.Separator))
+	if fileFilter == nil {
+		for i, part := range parts[:len(parts)-1] {
+			if newName, ok := globalRenames[part]; ok {
+				parts[i] = newName
+			}
+		}
+	}
+
+	last := len(parts) - 1
+	if inFileFilter(path) && !opts.dirsOnly {
+		for _, suffix := range append(append([]string{}, componentExtensions...), siblingSuffixes...) {
+			base := strings.TrimSuffix(parts[last], suffix)
+			if base == parts[last] {
+				continue
+			}
+			if newName, ok := globalRenames[base]; ok {
+				if slices.Contains(componentExtensions, suffix) {
+					newName = componentFileName(filepath.Dir(path), base, newName)
+				}
+				parts[last] = newName + suffix
+				break
+			}
+		}
+	}
+
+	return filepath.Join(append([]string{root}, parts...)...)
+}
+
+// affectedFiles lists, without touching disk, every file under dir whose
+// content would be rewritten or whose path would change.
+func affectedFiles(dir string) ([]string, error) {
+	previous := stdout
+	stdout = io.Discard
+	defer func() { stdout = previous }()
+
+	var affected []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		if plannedPath(dir, path) != path {
+			affected = append(affected, path)
+			return nil
+		}
+
+		if isSourceFile(d.Name()) && inFileFilter(path) {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if rewriteContent(path, string(content)) != string(content) {
+				affected = append(affected, path)
+			}
+		}
+		return nil
+	})
+
+	sort.Strings(affected)
+	return affected, err
+}
+
+func checkChangeLimit(count, limit int, force bool) error {
+	if limit <= 0 || count <= limit {
+		return nil
+	}
+	if force {
+		fmt.Fprintf(stdout, "Warning: %d files would change, exceeding --max-changes %d; continuing because --force was given.\n", count, limit)
+		return nil
+	}
+	return fmt.Errorf("%d files would change, which exceeds --max-changes %d. Re-run with --force to proceed anyway", count, limit)
+}
+
+// onlyRenames reports whether the run would rename files without editing the
+// content of any of them, which --assume-yes-on-clean treats as low risk.
+func onlyRenames(dir string) (bool, error) {
+	if len(opts.jsonPaths) > 0 {
+		return false, nil
+	}
+	p, err := buildPlan(dir)
+	if err != nil {
+		return false, err
+	}
+	return len(p.Edits) == 0, nil
+}
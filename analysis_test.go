@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAffectedFiles(t *testing.T) {
+	files := map[string]string{
+		"Button.vue":        `export default {}`,
+		"Button.stories.ts": `export default {}`,
+		"card.vue":          `import Button from './Button.vue'`,
+		"utils.ts":          `export const cn = () => ''`,
+		"Dialog/index.ts":   `export const x = 1`,
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = map[string]string{"Button": "button", "Dialog": "dialog"}
+	affected, err := affectedFiles(tmpDir)
+	if err != nil {
+		t.Fatalf("affectedFiles failed: %v", err)
+	}
+
+	expected := []string{
+		filepath.Join(tmpDir, "Button.stories.ts"),
+		filepath.Join(tmpDir, "Button.vue"),
+		filepath.Join(tmpDir, "Dialog", "index.ts"),
+		filepath.Join(tmpDir, "card.vue"),
+	}
+	if len(affected) != len(expected) {
+		t.Fatalf("affectedFiles() = %v; want %v", affected, expected)
+	}
+	for i := range expected {
+		if affected[i] != expected[i] {
+			t.Errorf("affectedFiles() = %v; want %v", affected, expected)
+			break
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "Button.vue")); err != nil {
+		t.Errorf("affectedFiles must not rename anything: %v", err)
+	}
+	content, _ := os.ReadFile(filepath.Join(tmpDir, "card.vue"))
+	if string(content) != files["card.vue"] {
+		t.Errorf("affectedFiles must not rewrite anything:\n%s", content)
+	}
+}
+
+func TestCheckChangeLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int
+		limit   int
+		force   bool
+		wantErr bool
+	}{
+		{"no limit", 500, 0, false, false},
+		{"under limit", 3, 5, false, false},
+		{"at limit", 5, 5, false, false},
+		{"over limit", 6, 5, false, true},
+		{"over limit with force", 6, 5, true, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkChangeLimit(tc.count, tc.limit, tc.force)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("checkChangeLimit(%d, %d, %v) = %v; wantErr %v", tc.count, tc.limit, tc.force, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestOnlyRenames(t *testing.T) {
+	globalRenames = map[string]string{"Button": "button"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected bool
+	}{
+		{
+			name:     "case-only rename",
+			files:    map[string]string{"Button.vue": `export default {}`},
+			expected: true,
+		},
+		{
+			name: "rename with content edit",
+			files: map[string]string{
+				"Button.vue": `export default {}`,
+				"Card.vue":   `import Button from './Button.vue'`,
+			},
+			expected: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+
+			writeFiles(t, tmpDir, tc.files)
+
+			result, err := onlyRenames(tmpDir)
+			if err != nil {
+				t.Fatalf("onlyRenames failed: %v", err)
+			}
+			if result != tc.expected {
+				t.Errorf("onlyRenames() = %v; want %v", result, tc.expected)
+			}
+		})
+	}
+}
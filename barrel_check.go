@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type barrelMismatch struct {
+	barrel    string
+	specifier string
+}
+
+// checkBarrels is --check-barrel: every relative specifier in a barrel under
+// dir has to name a file or folder that is on disk, letter case included, so
+// a barrel still pointing at a component's old name after an incomplete
+// rename is caught.
+func checkBarrels(dir string) ([]barrelMismatch, error) {
+	var mismatches []barrelMismatch
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isBarrelFile(d.Name()) {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, specifier := range importSpecifiers(string(content)) {
+			if isRelativeSpecifier(specifier) && !resolvesOnDisk(filepath.Dir(path), specifier) {
+				mismatches = append(mismatches, barrelMismatch{barrel: path, specifier: specifier})
+			}
+		}
+		return nil
+	})
+	return mismatches, err
+}
+
+// resolvesOnDisk reports whether specifier, relative to dir, names a file
+// with one of the resolveExtensions or a folder with a barrel.
+func resolvesOnDisk(dir, specifier string) bool {
+	rel := filepath.FromSlash(specifier)
+	candidates := make([]string, 0, len(resolveExtensions)+len(barrelFileNames))
+	for _, ext := range resolveExtensions {
+		candidates = append(candidates, rel+ext)
+	}
+	for _, barrel := range barrelFileNames {
+		candidates = append(candidates, filepath.Join(rel, barrel))
+	}
+	for _, candidate := range candidates {
+		if existsExactly(dir, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// existsExactly reports whether rel exists under dir with every segment
+// spelled as it is on disk, which os.Stat does not check on case-insensitive
+// file systems.
+func existsExactly(dir, rel string) bool {
+	current := dir
+	for _, segment := range strings.Split(filepath.Clean(rel), string(filepath.Separator)) {
+		if segment == "." || segment == ".." {
+			current = filepath.Join(current, segment)
+			continue
+		}
+		entries, err := os.ReadDir(current)
+		if err != nil {
+			return false
+		}
+		found := false
+		for _, entry := range entries {
+			if entry.Name() == segment {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+		current = filepath.Join(current, segment)
+	}
+	return true
+}
+
+func printBarrelMismatches(w io.Writer, mismatches []barrelMismatch) {
+	fmt.Fprintf(w, "\nBarrel check: %d re-export(s) point at files that do not exist:\n", len(mismatches))
+	for _, m := range mismatches {
+		fmt.Fprintf(w, "  %s: %s\n", displayPath(m.barrel), m.specifier)
+	}
+}
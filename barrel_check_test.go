@@ -0,0 +1,33 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckBarrels(t *testing.T) {
+	files := map[string]string{
+		"dialog/dialog.vue":         `export default {}`,
+		"dialog/dialog-content.vue": `export default {}`,
+		"dialog/dialog-foo.vue":     `export default {}`,
+		"dialog/index.ts": `export { default as Dialog } from './dialog.vue'
+export { default as DialogContent } from './dialog-content'
+export { default as DialogFoo } from './DialogFoo.vue'
+export * from '../button'
+// export { default as DialogOld } from './DialogOld.vue'
+export { cn } from '@/lib/utils'`,
+		"button/button.vue": `export default {}`,
+		"button/index.ts":   `export { default as Button } from './button.vue'`,
+		"index.ts":          `export * from './dialog'`,
+	}
+	tmpDir := writeTree(t, files)
+
+	mismatches, err := checkBarrels(tmpDir)
+	if err != nil {
+		t.Fatalf("checkBarrels failed: %v", err)
+	}
+	want := barrelMismatch{barrel: filepath.Join(tmpDir, "dialog", "index.ts"), specifier: "./DialogFoo.vue"}
+	if len(mismatches) != 1 || mismatches[0] != want {
+		t.Errorf("mismatches = %+v; want [%+v]", mismatches, want)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// nameCases are the forms --from-case and --case accept.
+var nameCases = []string{"pascal", "kebab", "snake"}
+
+// outputOnlyCases can only be written: a flat name such as buttongroup has no
+// word boundaries left to read back.
+var outputOnlyCases = []string{"flat"}
+
+func isNameCase(s string) bool {
+	for _, c := range nameCases {
+		if s == c {
+			return true
+		}
+	}
+	return false
+}
+
+func isOutputCase(s string) bool {
+	return isNameCase(s) || slices.Contains(outputOnlyCases, s)
+}
+
+// nameWords splits a component name written in the --from-case form into
+// its lower-case words.
+func nameWords(name string) []string {
+	switch opts.fromCase {
+	case "kebab":
+		return strings.Split(name, "-")
+	case "snake":
+		return strings.Split(name, "_")
+	}
+	return strings.Split(toKebabCase(name), "-")
+}
+
+// toOutputCase converts a name in the --from-case form to the --case form,
+// kebab-case by default.
+func toOutputCase(name string) string {
+	return convertName(name, opts.stripUIPrefix)
+}
+
+// convertName is toOutputCase, optionally dropping a leading ui word
+// (--strip-ui-prefix) so Nuxt's Ui auto-prefix does not make UiUiButton.
+func convertName(name string, stripUI bool) string {
+	words := nameWords(name)
+	if stripUI && len(words) > 1 && words[0] == "ui" {
+		words = words[1:]
+	}
+	switch opts.outputCase {
+	case "snake":
+		return strings.Join(words, "_")
+	case "pascal":
+		return joinPascal(words)
+	case "flat":
+		return strings.Join(words, "")
+	}
+	return strings.Join(words, "-")
+}
+
+// caseName is how the --case form is spelled in messages.
+func caseName() string {
+	switch opts.outputCase {
+	case "snake":
+		return "snake_case"
+	case "pascal":
+		return "PascalCase"
+	case "flat":
+		return "flat lower-case"
+	}
+	return "kebab-case"
+}
+
+func joinPascal(words []string) string {
+	var b strings.Builder
+	for _, word := range words {
+		if word != "" {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+var casedNameRegex = map[string]*regexp.Regexp{
+	"kebab": regexp.MustCompile(`^[a-z][a-z0-9]*(?:-[a-z0-9]+)*$`),
+	"snake": regexp.MustCompile(`^[a-z][a-z0-9]*(?:_[a-z0-9]+)*$`),
+}
+
+// isComponentName reports whether name is a component name in the
+// --from-case form: PascalCase by default, otherwise a kebab or snake name
+// whose PascalCase form would be detected. The prefix has to end on a word
+// boundary, so format_date is not taken for a Form component.
+func isComponentName(name string) bool {
+	re, ok := casedNameRegex[opts.fromCase]
+	if !ok {
+		return isPascalCase(name)
+	}
+	words := nameWords(name)
+	if !re.MatchString(name) || !isPascalCase(joinPascal(words)) {
+		return false
+	}
+	for _, prefix := range componentPrefixes {
+		prefixWords := strings.Split(toKebabCase(prefix), "-")
+		if len(prefixWords) <= len(words) && slices.Equal(words[:len(prefixWords)], prefixWords) {
+			return true
+		}
+	}
+	return false
+}
+
+var specifierRegex = regexp.MustCompile(`(?:from|import)\s*['"]([^'"]+)['"]`)
+
+// findComponentImports is findPascalCaseImports for the --from-case form.
+// Identifiers are PascalCase whatever the file names are, so a kebab or
+// snake input is detected from the path segments of import specifiers.
+func findComponentImports(content string) []string {
+	if _, ok := casedNameRegex[opts.fromCase]; !ok {
+		return findPascalCaseImports(content)
+	}
+
+	found := make(map[string]bool)
+	var results []string
+	spans := commentSpans(content)
+	for _, match := range specifierRegex.FindAllStringSubmatchIndex(content, -1) {
+		if inComment(spans, match[0]) {
+			continue
+		}
+		for _, segment := range strings.Split(content[match[2]:match[3]], "/") {
+			name := segment
+			for _, ext := range componentExtensions {
+				name = strings.TrimSuffix(name, ext)
+			}
+			if !found[name] && isComponentName(name) {
+				found[name] = true
+				results = append(results, name)
+			}
+		}
+	}
+	return results
+}
+
+// foldedRenameName finds the rename-map name that name spells with different
+// letter case, for --ignore-case-in-detection: alertDialog or ALERTDIALOG is
+// taken for AlertDialog. An all-lower-case spelling is never folded, since it
+// may be another convention's name for an unrelated file.
+func foldedRenameName(name string) (string, bool) {
+	if !opts.ignoreCaseInDetection || strings.ToLower(name) == name {
+		return "", false
+	}
+	for _, oldName := range sortedRenameNames() {
+		if oldName != name && strings.EqualFold(oldName, name) {
+			return oldName, true
+		}
+	}
+	return "", false
+}
+
+// replaceFolded is the --ignore-case-in-detection pass of a rewrite pattern
+// compiled with the component name in a (?i:) group. The name is what lies
+// between the pattern's two capture groups; matches where it is all lower
+// case are kept, as foldedRenameName does.
+func replaceFolded(re *regexp.Regexp, statement, replacement string) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range re.FindAllStringSubmatchIndex(statement, -1) {
+		name := statement[match[3]:match[4]]
+		if strings.ToLower(name) == name {
+			continue
+		}
+		b.WriteString(statement[last:match[0]])
+		b.Write(re.ExpandString(nil, replacement, statement, match))
+		last = match[1]
+	}
+	b.WriteString(statement[last:])
+	return b.String()
+}
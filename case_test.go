@@ -0,0 +1,237 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestToOutputCase(t *testing.T) {
+	defer func() { opts = options{} }()
+
+	tests := []struct {
+		fromCase, outputCase, name, expected string
+	}{
+		{"pascal", "kebab", "AlertDialog", "alert-dialog"},
+		{"pascal", "snake", "AlertDialog", "alert_dialog"},
+		{"snake", "kebab", "alert_dialog", "alert-dialog"},
+		{"snake", "pascal", "alert_dialog_content", "AlertDialogContent"},
+		{"kebab", "snake", "alert-dialog", "alert_dialog"},
+		{"pascal", "flat", "Button", "button"},
+		{"pascal", "flat", "ButtonGroup", "buttongroup"},
+		{"pascal", "flat", "AlertDialogContent", "alertdialogcontent"},
+		{"snake", "flat", "navigation_menu_item", "navigationmenuitem"},
+		{"", "", "OAuthButton", "oauth-button"},
+	}
+	for _, tt := range tests {
+		opts = options{fromCase: tt.fromCase, outputCase: tt.outputCase}
+		if result := toOutputCase(tt.name); result != tt.expected {
+			t.Errorf("toOutputCase(%q) from %s to %s = %q; want %q", tt.name, tt.fromCase, tt.outputCase, result, tt.expected)
+		}
+	}
+}
+
+func TestSnakeToKebab(t *testing.T) {
+	opts = options{fromCase: "snake", outputCase: "kebab"}
+	defer func() { opts = options{} }()
+	previous := stdout
+	stdout = io.Discard
+	defer func() { stdout = previous }()
+	defer func() { globalRenames = make(map[string]string) }()
+
+	files := map[string]string{
+		"alert_dialog/index.ts": `export { default as AlertDialog } from './alert_dialog.vue'
+export { default as AlertDialogContent } from './alert_dialog_content.vue'`,
+		"alert_dialog/alert_dialog.vue":         `export default {}`,
+		"alert_dialog/alert_dialog_content.vue": `export default {}`,
+		"button/button.vue":                     `export default {}`,
+		"App.vue": `<script setup lang="ts">
+import { AlertDialog } from '@/components/ui/alert_dialog'
+import Button from '@/components/ui/button/button.vue'
+import { format_date } from '@/lib/format_date'
+</script>`,
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = make(map[string]string)
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	want := map[string]string{"alert_dialog": "alert-dialog", "alert_dialog_content": "alert-dialog-content"}
+	if len(globalRenames) != len(want) {
+		t.Errorf("globalRenames = %v; want %v", globalRenames, want)
+	}
+	for oldName, newName := range want {
+		if globalRenames[oldName] != newName {
+			t.Errorf("globalRenames[%s] = %q; want %q", oldName, globalRenames[oldName], newName)
+		}
+	}
+
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+	expected := map[string]string{
+		"alert-dialog/index.ts": `export { default as AlertDialog } from './alert-dialog.vue'
+export { default as AlertDialogContent } from './alert-dialog-content.vue'`,
+		"alert-dialog/alert-dialog.vue":         `export default {}`,
+		"alert-dialog/alert-dialog-content.vue": `export default {}`,
+		"App.vue": `<script setup lang="ts">
+import { AlertDialog } from '@/components/ui/alert-dialog'
+import Button from '@/components/ui/button/button.vue'
+import { format_date } from '@/lib/format_date'
+</script>`,
+	}
+	for path, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tmpDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", path, want, content)
+		}
+	}
+}
+
+func TestFlatCaseRewrite(t *testing.T) {
+	opts = options{fromCase: "pascal", outputCase: "flat"}
+	defer func() { opts = options{} }()
+	defer func() { globalRenames = make(map[string]string) }()
+
+	tmpDir := t.TempDir()
+
+	filePath := filepath.Join(tmpDir, "App.vue")
+	content := `import { ButtonGroup } from '@/components/ui/ButtonGroup'
+import { AlertDialog } from '@/components/ui/AlertDialog'
+import AlertDialogContent from '@/components/ui/AlertDialog/AlertDialogContent.vue'
+import Button from '@/components/ui/Button/Button.vue'`
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	globalRenames = make(map[string]string)
+	for _, name := range findComponentImports(content) {
+		addDetectedRename(name)
+	}
+	if err := updateFileContent(filePath); err != nil {
+		t.Fatalf("updateFileContent failed: %v", err)
+	}
+
+	got, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	want := `import { ButtonGroup } from '@/components/ui/buttongroup'
+import { AlertDialog } from '@/components/ui/alertdialog'
+import AlertDialogContent from '@/components/ui/alertdialog/alertdialogcontent.vue'
+import Button from '@/components/ui/button/button.vue'`
+	if string(got) != want {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", want, got)
+	}
+}
+
+func TestStripUIPrefix(t *testing.T) {
+	defer func() { opts = options{} }()
+	previous := stdout
+	stdout = io.Discard
+	defer func() { stdout = previous }()
+
+	tests := []struct {
+		name     string
+		strip    bool
+		expected string
+	}{
+		{"UIButton", false, "ui-button"},
+		{"UIButton", true, "button"},
+		{"UiCard", true, "card"},
+		{"UI", true, "ui"},
+		{"MyUIKit", true, "my-ui-kit"},
+	}
+	for _, tt := range tests {
+		opts = options{stripUIPrefix: tt.strip}
+		if result := toOutputCase(tt.name); result != tt.expected {
+			t.Errorf("toOutputCase(%q) with strip=%v = %q; want %q", tt.name, tt.strip, result, tt.expected)
+		}
+	}
+
+	// Stripping must not send two components to the same name.
+	opts = options{stripUIPrefix: true}
+	globalRenames = map[string]string{"Button": "button"}
+	defer func() { globalRenames = make(map[string]string) }()
+	if newName, ok := addDetectedRename("UIButton"); !ok || newName != "ui-button" {
+		t.Errorf("addDetectedRename(%q) next to Button = %q, %v; want %q", "UIButton", newName, ok, "ui-button")
+	}
+	if newName, ok := addDetectedRename("UICard"); !ok || newName != "card" {
+		t.Errorf("addDetectedRename(%q) = %q, %v; want %q", "UICard", newName, ok, "card")
+	}
+}
+
+func TestIgnoreCaseInDetection(t *testing.T) {
+	previous := stdout
+	stdout = io.Discard
+	defer func() {
+		stdout = previous
+		opts = options{}
+		componentIndex = nil
+		globalRenames = make(map[string]string)
+	}()
+
+	input := `import A from '@/components/ui/alertDialog/AlertDialog.vue'
+import B from './BUTTON.vue'
+import C from './alertdialog'
+import { alertDialog } from '@/lib/alertDialog'
+import D from '@/components/ui/Button'`
+
+	tests := []struct {
+		name     string
+		ignore   bool
+		expected string
+	}{
+		{
+			name: "exact case only",
+			expected: `import A from '@/components/ui/alertDialog/alert-dialog.vue'
+import B from './BUTTON.vue'
+import C from './alertdialog'
+import { alertDialog } from '@/lib/alertDialog'
+import D from '@/components/ui/button'`,
+		},
+		{
+			name:   "ignore case",
+			ignore: true,
+			expected: `import A from '@/components/ui/alert-dialog/alert-dialog.vue'
+import B from './button.vue'
+import C from './alertdialog'
+import { alertDialog } from '@/lib/alertDialog'
+import D from '@/components/ui/button'`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts = options{ignoreCaseInDetection: tt.ignore}
+			globalRenames = map[string]string{"AlertDialog": "alert-dialog", "Button": "button"}
+			if result := rewriteContent("App.vue", input); result != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+
+	t.Run("resolved through the index", func(t *testing.T) {
+		files := map[string]string{
+			"AlertDialog/AlertDialog.vue": `export default {}`,
+			"Card.vue": `import AlertDialog from './alertDialog/ALERTDIALOG.vue'
+import Other from './Other/alertdialog.vue'`,
+		}
+		tmpDir := writeTree(t, files)
+
+		opts = options{ignoreCaseInDetection: true}
+		globalRenames = map[string]string{"AlertDialog": "alert-dialog"}
+		if err := indexComponents(tmpDir); err != nil {
+			t.Fatalf("indexComponents failed: %v", err)
+		}
+		want := `import AlertDialog from './alert-dialog/alert-dialog.vue'
+import Other from './Other/alertdialog.vue'`
+		if result := rewriteContent(filepath.Join(tmpDir, "Card.vue"), files["Card.vue"]); result != want {
+			t.Errorf("Expected:\n%s\n\nGot:\n%s", want, result)
+		}
+	})
+}
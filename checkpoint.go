@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const checkpointFileName = ".rename-shadcn-vue.checkpoint"
+
+// The checkpoint records the rename map and every file whose content has
+// been processed, one tab-separated entry per line, so an interrupted run can
+// be resumed with --resume.
+type checkpoint struct {
+	dir     string
+	renames map[string]string
+	done    map[string]bool
+	file    *os.File
+}
+
+var activeCheckpoint *checkpoint
+
+func loadCheckpoint(dir string) (*checkpoint, error) {
+	c := &checkpoint{
+		dir:     dir,
+		renames: make(map[string]string),
+		done:    make(map[string]bool),
+	}
+
+	file, err := os.Open(filepath.Join(dir, checkpointFileName))
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		switch {
+		case len(fields) == 3 && fields[0] == "rename":
+			c.renames[fields[1]] = fields[2]
+		case len(fields) == 2 && fields[0] == "done":
+			c.done[fields[1]] = true
+		}
+	}
+	return c, scanner.Err()
+}
+
+func (c *checkpoint) start(renames map[string]string) error {
+	file, err := os.Create(filepath.Join(c.dir, checkpointFileName))
+	if err != nil {
+		return err
+	}
+	c.file = file
+
+	for oldName, newName := range renames {
+		if _, err := fmt.Fprintf(file, "rename\t%s\t%s\n", oldName, newName); err != nil {
+			return err
+		}
+	}
+	for path := range c.done {
+		if _, err := fmt.Fprintf(file, "done\t%s\n", path); err != nil {
+			return err
+		}
+	}
+	return file.Sync()
+}
+
+func (c *checkpoint) key(path string) string {
+	if rel, err := filepath.Rel(c.dir, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
+
+func (c *checkpoint) isDone(path string) bool {
+	return c != nil && c.done[c.key(path)]
+}
+
+func (c *checkpoint) markDone(path string) error {
+	if c == nil || c.file == nil {
+		return nil
+	}
+	key := c.key(path)
+	c.done[key] = true
+	if _, err := fmt.Fprintf(c.file, "done\t%s\n", key); err != nil {
+		return err
+	}
+	return c.file.Sync()
+}
+
+func (c *checkpoint) finish() error {
+	if c == nil || c.file == nil {
+		return nil
+	}
+	if err := c.file.Close(); err != nil {
+		return err
+	}
+	return os.Remove(c.file.Name())
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResumeSkipsProcessedFiles(t *testing.T) {
+	files := map[string]string{
+		"Button.vue": `import Card from './Card.vue'
+export default {}`,
+		"Card.vue": `import Button from './Button.vue'
+export default {}`,
+	}
+	tmpDir := writeTree(t, files)
+
+	interrupted := "rename\tButton\tbutton\nrename\tCard\tcard\ndone\tCard.vue\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, checkpointFileName), []byte(interrupted), 0644); err != nil {
+		t.Fatalf("Failed to write checkpoint: %v", err)
+	}
+
+	var err error
+	activeCheckpoint, err = loadCheckpoint(tmpDir)
+	if err != nil {
+		t.Fatalf("loadCheckpoint failed: %v", err)
+	}
+	defer func() { activeCheckpoint = nil }()
+
+	if activeCheckpoint.renames["Card"] != "card" || !activeCheckpoint.done["Card.vue"] {
+		t.Fatalf("checkpoint not loaded correctly: %+v", activeCheckpoint)
+	}
+
+	globalRenames = activeCheckpoint.renames
+	if err := activeCheckpoint.start(globalRenames); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+	if err := activeCheckpoint.finish(); err != nil {
+		t.Fatalf("finish failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "card.vue"))
+	if err != nil {
+		t.Fatalf("Failed to read card.vue: %v", err)
+	}
+	if string(content) != files["Card.vue"] {
+		t.Errorf("already processed Card.vue was rewritten again:\n%s", content)
+	}
+
+	content, err = os.ReadFile(filepath.Join(tmpDir, "button.vue"))
+	if err != nil {
+		t.Fatalf("Failed to read button.vue: %v", err)
+	}
+	if string(content) != "import Card from './card.vue'\nexport default {}" {
+		t.Errorf("unprocessed Button.vue was not rewritten:\n%s", content)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, checkpointFileName)); !os.IsNotExist(err) {
+		t.Errorf("checkpoint should be removed after a successful run")
+	}
+}
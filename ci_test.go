@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestCIMode runs the real main in a child process with stdin closed, so a
+// confirmation prompt would fail the run instead of hanging.
+func TestCIMode(t *testing.T) {
+	if dir := os.Getenv("RENAME_SHADCN_VUE_CI_DIR"); dir != "" {
+		os.Args = []string{"rename-shadcn-vue", "--ci", dir}
+		main()
+		os.Exit(0)
+	}
+
+	files := map[string]string{
+		"ui/Button/Button.vue": `export default {}`,
+		"ui/Button/index.ts":   `export { default as Button } from './Button.vue'`,
+	}
+	tmpDir := writeTree(t, files)
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestCIMode$")
+	cmd.Env = append(os.Environ(), "RENAME_SHADCN_VUE_CI_DIR="+filepath.Join(tmpDir, "ui"))
+	cmd.Dir = tmpDir
+	output, err := cmd.Output()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("--ci with pending changes: err = %v; want exit code 1\n%s", err, output)
+	}
+	var r report
+	if err := json.Unmarshal(output, &r); err != nil {
+		t.Fatalf("--ci output is not a JSON report: %v\n%s", err, output)
+	}
+	if r.Counts.Components != 1 || r.Counts.PathsRenamed == 0 || r.Plan != nil {
+		t.Errorf("report = %+v; want one component, its renames and no plan", r)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "ui", "Button", "Button.vue")); err != nil {
+		t.Errorf("--ci touched disk: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "ui", lockFileName)); !os.IsNotExist(err) {
+		t.Errorf("lock left behind after the run: %v", err)
+	}
+}
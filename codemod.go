@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// codemod is the --emit-codemod description of a run, for jscodeshift or
+// ts-morph transforms that replay it where this tool does not reach. Each
+// rename carries a JavaScript regular expression for the component's path
+// segment in a specifier; paths and specifiers list what this run changed,
+// with paths as they were before it.
+type codemod struct {
+	Renames    []codemodRename    `json:"renames"`
+	Paths      []pathRename       `json:"paths"`
+	Specifiers []codemodSpecifier `json:"specifiers"`
+}
+
+type codemodRename struct {
+	Name    string `json:"name"`
+	NewName string `json:"newName"`
+	Match   string `json:"match"`
+	Replace string `json:"replace"`
+}
+
+type codemodSpecifier struct {
+	File string `json:"file"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+var (
+	codemodSpecifiers   []codemodSpecifier
+	codemodSpecifiersMu sync.Mutex
+)
+
+// recordSpecifierChanges pairs up the import specifiers of a file before and
+// after it was rewritten and keeps the ones that changed. Rewriting never
+// adds or drops an import, so the two lists line up.
+func recordSpecifierChanges(filePath, before, after string) {
+	old := entryImportRegex.FindAllStringSubmatch(stripComments(before), -1)
+	updated := entryImportRegex.FindAllStringSubmatch(stripComments(after), -1)
+	if len(old) != len(updated) {
+		return
+	}
+
+	codemodSpecifiersMu.Lock()
+	defer codemodSpecifiersMu.Unlock()
+	for i := range old {
+		if old[i][1] != updated[i][1] {
+			codemodSpecifiers = append(codemodSpecifiers, codemodSpecifier{File: displayPath(filePath), From: old[i][1], To: updated[i][1]})
+		}
+	}
+}
+
+func buildCodemod() codemod {
+	c := codemod{
+		Renames:    []codemodRename{},
+		Paths:      []pathRename{},
+		Specifiers: []codemodSpecifier{},
+	}
+	for _, name := range sortedRenameNames() {
+		c.Renames = append(c.Renames, codemodRename{
+			Name:    name,
+			NewName: globalRenames[name],
+			Match:   `(^|/)` + regexp.QuoteMeta(name) + `(?=[/.]|$)`,
+			Replace: "$1" + globalRenames[name],
+		})
+	}
+	for _, rename := range renamedPaths {
+		c.Paths = append(c.Paths, pathRename{From: displayPath(rename.From), To: displayPath(rename.To)})
+	}
+
+	codemodSpecifiersMu.Lock()
+	defer codemodSpecifiersMu.Unlock()
+	c.Specifiers = append(c.Specifiers, codemodSpecifiers...)
+	sort.SliceStable(c.Specifiers, func(i, j int) bool { return c.Specifiers[i].File < c.Specifiers[j].File })
+	return c
+}
+
+func writeCodemod(path string, c codemod) error {
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
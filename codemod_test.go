@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEmitCodemod(t *testing.T) {
+	previous := stdout
+	stdout = io.Discard
+	defer func() {
+		stdout = previous
+		opts = options{}
+		pathBase = ""
+		globalRenames = make(map[string]string)
+		rewrittenFiles = make(map[string]bool)
+		changedFiles = make(map[string]bool)
+		renamedPaths = nil
+		codemodSpecifiers = nil
+	}()
+
+	files := map[string]string{
+		"Button/Button.vue": `export default {}`,
+		"Card.vue": `import Button from './Button/Button.vue'
+import { cn } from '@/lib/utils'`,
+	}
+	tmpDir := writeTree(t, files)
+
+	opts = options{emitCodemod: filepath.Join(tmpDir, "codemod.json")}
+	pathBase = tmpDir
+	globalRenames = make(map[string]string)
+	rewrittenFiles = make(map[string]bool)
+	renamedPaths = nil
+	codemodSpecifiers = nil
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+	if err := writeCodemod(opts.emitCodemod, buildCodemod()); err != nil {
+		t.Fatalf("writeCodemod failed: %v", err)
+	}
+
+	content, err := os.ReadFile(opts.emitCodemod)
+	if err != nil {
+		t.Fatalf("Failed to read codemod description: %v", err)
+	}
+	var got codemod
+	if err := json.Unmarshal(content, &got); err != nil {
+		t.Fatalf("Failed to parse codemod description: %v", err)
+	}
+
+	want := codemod{
+		Renames: []codemodRename{
+			{Name: "Button", NewName: "button", Match: `(^|/)Button(?=[/.]|$)`, Replace: "$1button"},
+		},
+		Paths: []pathRename{
+			{From: "Button/Button.vue", To: "Button/button.vue"},
+			{From: "Button", To: "button"},
+		},
+		Specifiers: []codemodSpecifier{
+			{File: "Card.vue", From: "./Button/Button.vue", To: "./button/button.vue"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("codemod description:\n%s\nwant %+v", content, want)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// collapsedDirs holds the absolute paths of folders whose component file is
+// renamed to index.vue under --collapse-to-index, keyed as they are on disk
+// before the run.
+var collapsedDirs map[string]bool
+
+// findCollapsibleFolders returns the folders under root named after a renamed
+// component whose only component file carries the folder's name, such as
+// Dialog/Dialog.vue.
+func findCollapsibleFolders(root string) (map[string]bool, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	collapsed := make(map[string]bool)
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if _, ok := globalRenames[d.Name()]; !ok {
+			return nil
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		var components []string
+		for _, entry := range entries {
+			if !entry.IsDir() && slices.Contains(componentExtensions, filepath.Ext(entry.Name())) {
+				components = append(components, entry.Name())
+			}
+		}
+		if len(components) == 1 && strings.TrimSuffix(components[0], filepath.Ext(components[0])) == d.Name() {
+			collapsed[path] = true
+		}
+		return nil
+	})
+	return collapsed, err
+}
+
+// componentFileName is the name, without extension, that the oldName
+// component file in dir is renamed to: newName, or "index" when dir has been
+// collapsed.
+func componentFileName(dir, oldName, newName string) string {
+	if !opts.collapseToIndex || oldName != filepath.Base(dir) {
+		return newName
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil || !collapsedDirs[abs] {
+		return newName
+	}
+	return "index"
+}
+
+// resolveCollapsedUISpecifier resolves a ui-aliased specifier through the
+// component index, so @/components/ui/Dialog/Dialog.vue follows a collapsed
+// folder to dialog/index.vue instead of the name-based dialog/dialog.vue. It
+// reports false when a segment is not indexed.
+func resolveCollapsedUISpecifier(specifier string) (string, bool) {
+	prefix, rest := splitUISpecifier(specifier)
+	if rest == "" {
+		return specifier, false
+	}
+	resolved := resolveSegments(locateUIDir(componentRoot), rest)
+	if resolved == rest {
+		return specifier, false
+	}
+	return prefix + resolved, true
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCollapseToIndex(t *testing.T) {
+	defer func() {
+		componentIndex = nil
+		collapsedDirs = nil
+		opts.collapseToIndex = false
+		globalRenames = make(map[string]string)
+	}()
+
+	files := map[string]string{
+		"Dialog/Dialog.vue":      `export default {}`,
+		"Dialog/index.ts":        `export { default as Dialog } from './Dialog.vue'`,
+		"Button/Button.vue":      `export default {}`,
+		"Button/ButtonGroup.vue": `export default {}`,
+		"Card.vue": `import Dialog from './Dialog/Dialog.vue'
+import { Dialog as D } from './Dialog/Dialog'
+import Button from './Button/Button.vue'
+import ButtonGroup from './Button/ButtonGroup.vue'`,
+		"App.vue": `import Dialog from '@/components/ui/Dialog/Dialog.vue'
+import { Dialog as D } from '@/components/ui/Dialog'
+import Button from '@/components/ui/Button/Button.vue'`,
+	}
+	tmpDir := writeTree(t, files)
+
+	opts.collapseToIndex = true
+	globalRenames = make(map[string]string)
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if err := indexComponents(tmpDir); err != nil {
+		t.Fatalf("indexComponents failed: %v", err)
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"dialog/index.vue":        files["Dialog/Dialog.vue"],
+		"dialog/index.ts":         `export { default as Dialog } from './index.vue'`,
+		"button/button.vue":       files["Button/Button.vue"],
+		"button/button-group.vue": files["Button/ButtonGroup.vue"],
+		"Card.vue": `import Dialog from './dialog/index.vue'
+import { Dialog as D } from './dialog/index'
+import Button from './button/button.vue'
+import ButtonGroup from './button/button-group.vue'`,
+		"App.vue": `import Dialog from '@/components/ui/dialog/index.vue'
+import { Dialog as D } from '@/components/ui/dialog'
+import Button from '@/components/ui/button/button.vue'`,
+	}
+	for path, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tmpDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", path, want, content)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "dialog", "dialog.vue")); err == nil {
+		t.Error("dialog/dialog.vue exists; want it collapsed to index.vue")
+	}
+}
+
+func TestCollapseToIndexAboveUI(t *testing.T) {
+	defer func() {
+		componentIndex = nil
+		collapsedDirs = nil
+		opts.collapseToIndex = false
+		globalRenames = make(map[string]string)
+	}()
+
+	files := map[string]string{
+		"ui/Dialog/Dialog.vue": `export default {}`,
+		"App.vue":              `import Dialog from '@/components/ui/Dialog/Dialog.vue'`,
+	}
+	tmpDir := writeTree(t, files)
+
+	opts.collapseToIndex = true
+	globalRenames = make(map[string]string)
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if err := indexComponents(tmpDir); err != nil {
+		t.Fatalf("indexComponents failed: %v", err)
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	expected := `import Dialog from '@/components/ui/dialog/index.vue'`
+	content, err := os.ReadFile(filepath.Join(tmpDir, "App.vue"))
+	if err != nil {
+		t.Fatalf("Failed to read App.vue: %v", err)
+	}
+	if string(content) != expected {
+		t.Errorf("\nExpected:\n%s\n\nGot:\n%s", expected, content)
+	}
+}
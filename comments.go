@@ -0,0 +1,127 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// token is a run of content the tokenizer has classified as code, a quoted
+// string (', " or `) or a comment (//, /* */ or <!-- -->).
+type token struct {
+	kind       string
+	start, end int
+}
+
+// tokenize splits content into code, string and comment tokens. Quoted
+// strings are skipped so a // inside a URL is not a comment; ' and " strings
+// end at a newline, which keeps an apostrophe in template text from
+// swallowing the rest of the file.
+func tokenize(content string) []token {
+	var tokens []token
+	code := 0
+	add := func(kind string, start, end int) {
+		if code < start {
+			tokens = append(tokens, token{"code", code, start})
+		}
+		tokens = append(tokens, token{kind, start, end})
+		code = end
+	}
+	for i := 0; i < len(content); i++ {
+		switch c := content[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			start := i
+			for i++; i < len(content) && content[i] != c; i++ {
+				if content[i] == '\\' {
+					i++
+				} else if content[i] == '\n' && c != '`' {
+					break
+				}
+			}
+			end := min(i+1, len(content))
+			if i < len(content) && content[i] == '\n' {
+				end = i
+			}
+			add("string", start, end)
+		case strings.HasPrefix(content[i:], "//"):
+			end := strings.IndexByte(content[i:], '\n')
+			if end < 0 {
+				end = len(content) - i
+			}
+			add("comment", i, i+end)
+			i += end
+		case strings.HasPrefix(content[i:], "/*"):
+			span := commentSpan(content, i, "*/")
+			add("comment", span[0], span[1])
+			i = span[1] - 1
+		case strings.HasPrefix(content[i:], "<!--"):
+			span := commentSpan(content, i, "-->")
+			add("comment", span[0], span[1])
+			i = span[1] - 1
+		}
+	}
+	if code < len(content) {
+		tokens = append(tokens, token{"code", code, len(content)})
+	}
+	return tokens
+}
+
+// commentSpans returns the byte ranges of the comments in content.
+func commentSpans(content string) [][2]int {
+	var spans [][2]int
+	for _, t := range tokenize(content) {
+		if t.kind == "comment" {
+			spans = append(spans, [2]int{t.start, t.end})
+		}
+	}
+	return spans
+}
+
+// stripComments blanks out comments for detection, keeping newlines so line
+// structure is unchanged and putting a space in place of a block comment so
+// the words either side of it stay apart.
+func stripComments(content string) string {
+	var b strings.Builder
+	for _, t := range tokenize(content) {
+		text := content[t.start:t.end]
+		if t.kind == "comment" {
+			text = strings.Repeat("\n", strings.Count(text, "\n"))
+			if text == "" && !strings.HasPrefix(content[t.start:], "//") {
+				text = " "
+			}
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+func commentSpan(content string, start int, closing string) [2]int {
+	end := strings.Index(content[start+2:], closing)
+	if end < 0 {
+		return [2]int{start, len(content)}
+	}
+	return [2]int{start, start + 2 + end + len(closing)}
+}
+
+func inComment(spans [][2]int, pos int) bool {
+	i := sort.Search(len(spans), func(i int) bool { return spans[i][1] > pos })
+	return i < len(spans) && spans[i][0] <= pos
+}
+
+// replaceOutsideComments is ReplaceAllStringFunc that leaves matches starting
+// inside a comment untouched, so commented-out imports keep their old paths.
+func replaceOutsideComments(re *regexp.Regexp, content string, replace func(string) string) string {
+	spans := commentSpans(content)
+	var b strings.Builder
+	last := 0
+	for _, match := range re.FindAllStringIndex(content, -1) {
+		if inComment(spans, match[0]) {
+			continue
+		}
+		b.WriteString(content[last:match[0]])
+		b.WriteString(replace(content[match[0]:match[1]]))
+		last = match[1]
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const componentsJSONName = "components.json"
+
+type componentsJSON struct {
+	Aliases struct {
+		Components string `json:"components"`
+		UI         string `json:"ui"`
+	} `json:"aliases"`
+}
+
+// uiAliases holds import prefixes for the ui directory read from
+// components.json that the built-in @/ and ~/ patterns do not cover.
+var uiAliases []string
+
+func loadComponentsJSON(path string) (*componentsJSON, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("components.json not found: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", path, err)
+	}
+
+	unknown, err := validateComponentsJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("malformed components.json %s: %v", path, err)
+	}
+	for _, key := range unknown {
+		fmt.Fprintf(stdout, "Warning: ignoring unknown key %q in %s; known aliases are %s\n", key, path, strings.Join(componentsJSONAliases, ", "))
+	}
+	var config componentsJSON
+	if err := json.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("malformed components.json %s: %v", path, err)
+	}
+	return &config, nil
+}
+
+// discoverComponentsJSON loads the file given by --components-json, or a
+// components.json in the current directory. It returns nil when neither exists.
+func discoverComponentsJSON(override string) (*componentsJSON, string, error) {
+	path := override
+	if path == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, "", fmt.Errorf("error getting current directory: %v", err)
+		}
+		path = filepath.Join(cwd, componentsJSONName)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil, "", nil
+		}
+	}
+
+	config, err := loadComponentsJSON(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return config, path, nil
+}
+
+func (c *componentsJSON) uiAlias() string {
+	if c.Aliases.UI != "" {
+		return strings.TrimSuffix(c.Aliases.UI, "/")
+	}
+	if c.Aliases.Components != "" {
+		return strings.TrimSuffix(c.Aliases.Components, "/") + "/ui"
+	}
+	return ""
+}
+
+func (c *componentsJSON) uiDir(baseDir string) (string, error) {
+	alias := c.uiAlias()
+	if alias == "" {
+		return "", fmt.Errorf("components.json does not define aliases.ui or aliases.components")
+	}
+
+	relative := alias
+	if i := strings.Index(alias, "/"); i >= 0 && strings.ContainsAny(alias[:1], "@~#") {
+		relative = alias[i+1:]
+	}
+
+	for _, root := range []string{"", "src", "app"} {
+		path := filepath.Join(baseDir, root, filepath.FromSlash(relative))
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("could not resolve ui alias %q from components.json to a directory", alias)
+}
+
+// registerUIAlias records a ui alias such as #ui or @/shared/ui unless the
+// built-in @/components/ui patterns already cover it. A bare @ or ~ names the
+// whole source tree, not the ui folder, so it is never registered.
+func registerUIAlias(alias string) {
+	alias = strings.TrimSuffix(alias, "/")
+	switch alias {
+	case "", "@", "~", "@@", "~~":
+		return
+	}
+	if uiSpecifierRegex.MatchString(alias+"/") || slices.Contains(uiAliases, alias) {
+		return
+	}
+	uiAliases = append(uiAliases, alias)
+}
+
+func aliasPatterns(oldName, newName string) []rewritePattern {
+	var patterns []rewritePattern
+	for _, alias := range uiAliases {
+		patterns = append(patterns, rewritePattern{
+			fmt.Sprintf(`(['"]%s/%s)%s((?:\.vue)?['"/])`, regexp.QuoteMeta(alias), uiSubfolders, oldName),
+			fmt.Sprintf(`${1}%s${2}`, newName),
+		})
+	}
+	return patterns
+}
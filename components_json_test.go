@@ -0,0 +1,134 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadComponentsJSONErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	missing := filepath.Join(tmpDir, "components.json")
+	if _, _, err := discoverComponentsJSON(missing); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("discoverComponentsJSON(%q) error = %v; want not found error", missing, err)
+	}
+
+	malformed := filepath.Join(tmpDir, "malformed.json")
+	if err := os.WriteFile(malformed, []byte(`{"aliases": {`), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if _, _, err := discoverComponentsJSON(malformed); err == nil || !strings.Contains(err.Error(), "malformed") {
+		t.Errorf("discoverComponentsJSON(%q) error = %v; want malformed error", malformed, err)
+	}
+}
+
+func TestComponentsJSONUIDir(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	uiDir := filepath.Join(tmpDir, "src", "components", "ui")
+	if err := os.MkdirAll(uiDir, 0755); err != nil {
+		t.Fatalf("Failed to create ui dir: %v", err)
+	}
+
+	configPath := filepath.Join(tmpDir, "packages", "web", "components.json")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte(`{"aliases": {"components": "@/components", "ui": "@/components/ui"}}`), 0644); err != nil {
+		t.Fatalf("Failed to write components.json: %v", err)
+	}
+
+	config, path, err := discoverComponentsJSON(configPath)
+	if err != nil {
+		t.Fatalf("discoverComponentsJSON failed: %v", err)
+	}
+	if _, err := config.uiDir(filepath.Dir(path)); err == nil {
+		t.Errorf("uiDir() should fail when the alias does not resolve next to %s", path)
+	}
+
+	result, err := config.uiDir(tmpDir)
+	if err != nil {
+		t.Fatalf("uiDir failed: %v", err)
+	}
+	if result != uiDir {
+		t.Errorf("uiDir() = %q; want %q", result, uiDir)
+	}
+}
+
+func TestCustomUIAliasRewrite(t *testing.T) {
+	original := uiAliases
+	defer func() { uiAliases = original }()
+
+	registerUIAlias("@/components/ui")
+	if len(uiAliases) != len(original) {
+		t.Fatalf("built-in alias should not be registered: %v", uiAliases)
+	}
+	registerUIAlias("#ui")
+
+	globalRenames = map[string]string{"Button": "button"}
+	input := `import { Button } from '#ui/Button'
+import Card from '#ui/Button/Button.vue'`
+	expected := `import { Button } from '#ui/button'
+import Card from '#ui/button/button.vue'`
+	if result := rewriteImports("test.vue", input); result != expected {
+		t.Errorf("\nExpected:\n%s\n\nGot:\n%s", expected, result)
+	}
+}
+
+func TestComponentsJSONCustomUIAlias(t *testing.T) {
+	original := uiAliases
+	defer func() {
+		uiAliases = original
+		componentIndex = nil
+		globalRenames = make(map[string]string)
+	}()
+
+	files := map[string]string{
+		"components.json":                 `{"aliases": {"ui": "@/shared/ui"}}`,
+		"src/shared/ui/Button/Button.vue": `export default {}`,
+		"src/shared/ui/Button/index.ts":   `export { default as Button } from './Button.vue'`,
+		"src/shared/ui/Card/Card.vue": `import { Button } from '@/shared/ui/Button'
+import ButtonFile from '@/shared/ui/Button/Button.vue'`,
+	}
+	tmpDir := writeTree(t, files)
+
+	config, err := loadComponentsJSON(filepath.Join(tmpDir, "components.json"))
+	if err != nil {
+		t.Fatalf("loadComponentsJSON failed: %v", err)
+	}
+	registerUIAlias(config.uiAlias())
+	if !slices.Contains(uiAliases, "@/shared/ui") {
+		t.Fatalf("uiAliases = %v; want @/shared/ui registered", uiAliases)
+	}
+	dir, err := config.uiDir(tmpDir)
+	if err != nil {
+		t.Fatalf("uiDir failed: %v", err)
+	}
+
+	globalRenames = make(map[string]string)
+	if err := buildRenameMap(dir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if err := indexComponents(dir); err != nil {
+		t.Fatalf("indexComponents failed: %v", err)
+	}
+	if err := processFiles(dir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	expected := `import { Button } from '@/shared/ui/button'
+import ButtonFile from '@/shared/ui/button/button.vue'`
+	content, err := os.ReadFile(filepath.Join(dir, "Card", "Card.vue"))
+	if err != nil {
+		t.Fatalf("Failed to read Card.vue: %v", err)
+	}
+	if string(content) != expected {
+		t.Errorf("\nExpected:\n%s\n\nGot:\n%s", expected, content)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "button", "button.vue")); err != nil {
+		t.Errorf("Button folder was not renamed: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// decodeStringTable decodes a JSON object whose values must all be strings,
+// as used by --map and --aliases, naming the offending key when one is not.
+func decodeStringTable(content []byte) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(content, &raw); err != nil || raw == nil {
+		return nil, fmt.Errorf("want a JSON object of string values")
+	}
+
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	table := make(map[string]string, len(raw))
+	for _, key := range keys {
+		var value string
+		if err := json.Unmarshal(raw[key], &value); err != nil {
+			return nil, fmt.Errorf("%q must be a string, got %s", key, jsonKind(raw[key]))
+		}
+		table[key] = value
+	}
+	return table, nil
+}
+
+// componentsJSONAliases are the keys shadcn-vue defines under "aliases".
+// Other top-level keys of components.json belong to shadcn-vue and are not
+// checked.
+var componentsJSONAliases = []string{"components", "composables", "lib", "ui", "utils"}
+
+// validateComponentsJSON checks the aliases of a components.json. The file
+// belongs to shadcn-vue, which may add keys of its own, so unknown aliases
+// are returned to be warned about rather than failing the run.
+func validateComponentsJSON(content []byte) ([]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(content, &raw); err != nil || raw == nil {
+		return nil, fmt.Errorf("want a JSON object")
+	}
+	aliases, ok := raw["aliases"]
+	if !ok {
+		return nil, nil
+	}
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(aliases, &entries); err != nil || entries == nil {
+		return nil, fmt.Errorf(`"aliases" must be an object, got %s`, jsonKind(aliases))
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var unknown []string
+	for _, key := range keys {
+		if !slices.Contains(componentsJSONAliases, key) {
+			unknown = append(unknown, "aliases."+key)
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(entries[key], &value); err != nil {
+			return nil, fmt.Errorf(`"aliases.%s" must be a string, got %s`, key, jsonKind(entries[key]))
+		}
+	}
+	return unknown, nil
+}
+
+func jsonKind(raw json.RawMessage) string {
+	switch trimmed := strings.TrimSpace(string(raw)); {
+	case trimmed == "null":
+		return "null"
+	case trimmed == "true" || trimmed == "false":
+		return "boolean"
+	case strings.HasPrefix(trimmed, "{"):
+		return "an object"
+	case strings.HasPrefix(trimmed, "["):
+		return "an array"
+	case strings.HasPrefix(trimmed, `"`):
+		return "a string"
+	default:
+		return "a number"
+	}
+}
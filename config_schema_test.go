@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigValidationErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		load    func(path string) error
+		want    string
+	}{
+		{
+			name:    "rename map with a number",
+			content: `{"Button": "button", "Card": 3}`,
+			load:    func(path string) error { _, err := loadRenameMap(path); return err },
+			want:    `"Card" must be a string, got a number`,
+		},
+		{
+			name:    "rename map that is an array",
+			content: `["Button"]`,
+			load:    func(path string) error { _, err := loadRenameMap(path); return err },
+			want:    "want a JSON object of string values",
+		},
+		{
+			name:    "alias table with an array",
+			content: `{"components/ui": ["@ui"]}`,
+			load:    func(path string) error { _, err := loadAliasTable(path); return err },
+			want:    `"components/ui" must be a string, got an array`,
+		},
+		{
+			name:    "components.json with a non-string alias",
+			content: `{"aliases": {"ui": true}}`,
+			load:    func(path string) error { _, err := loadComponentsJSON(path); return err },
+			want:    `"aliases.ui" must be a string, got boolean`,
+		},
+		{
+			name:    "components.json with aliases as a string",
+			content: `{"style": "default", "aliases": "@/components"}`,
+			load:    func(path string) error { _, err := loadComponentsJSON(path); return err },
+			want:    `"aliases" must be an object, got a string`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(tmpDir, "config.json")
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+			err := tc.load(path)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("error = %v; want it to mention %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestComponentsJSONUnknownAlias(t *testing.T) {
+	var output bytes.Buffer
+	previous := stdout
+	stdout = &output
+	defer func() { stdout = previous }()
+
+	tmpDir := writeTree(t, map[string]string{
+		"components.json": `{"aliases": {"ui": "@/components/ui", "hooks": {"path": "@/hooks"}}}`,
+	})
+	config, err := loadComponentsJSON(filepath.Join(tmpDir, "components.json"))
+	if err != nil {
+		t.Fatalf("loadComponentsJSON failed on an alias added upstream: %v", err)
+	}
+	if config.Aliases.UI != "@/components/ui" {
+		t.Errorf("ui alias = %q; want @/components/ui", config.Aliases.UI)
+	}
+	if !strings.Contains(output.String(), `Warning: ignoring unknown key "aliases.hooks"`) {
+		t.Errorf("no warning for the unknown alias:\n%s", output.String())
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// stdin is where the confirmation prompt reads its answer from.
+var stdin io.Reader = os.Stdin
+
+// stderr takes the prompt while stdout is held back for --json or
+// --silent-if-clean, so the question is seen before it has to be answered.
+var stderr io.Writer = os.Stderr
+
+func promptOutput() io.Writer {
+	if quietBuffer != nil {
+		return stderr
+	}
+	return stdout
+}
+
+// confirmChanges asks --confirm-message and reports whether the answer was
+// yes. With --confirm-timeout, --confirm-default is taken as the answer when
+// no line arrives in time, so a semi-interactive gate neither blocks forever
+// nor loses the chance of a human override.
+func confirmChanges() bool {
+	prompt := promptOutput()
+	if opts.confirmTimeout <= 0 {
+		fmt.Fprintf(prompt, "\n%s (y/n): ", opts.confirmMessage)
+		response, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil {
+			fmt.Fprintf(prompt, "Error reading input: %v\n", err)
+			return false
+		}
+		return isYes(response)
+	}
+
+	fmt.Fprintf(prompt, "\n%s (y/n, %s in %v): ", opts.confirmMessage, opts.confirmDefault, opts.confirmTimeout)
+	type answer struct {
+		response string
+		err      error
+	}
+	// The read is left running on timeout; stdin is only read once per run.
+	// It reads from the reader captured here, as stdin may be swapped out
+	// (by a test) once this returns.
+	r := stdin
+	answers := make(chan answer, 1)
+	go func() {
+		response, err := bufio.NewReader(r).ReadString('\n')
+		answers <- answer{response, err}
+	}()
+
+	select {
+	case a := <-answers:
+		if a.err != nil {
+			fmt.Fprintf(prompt, "Error reading input: %v\n", a.err)
+			return false
+		}
+		return isYes(a.response)
+	case <-time.After(opts.confirmTimeout):
+		fmt.Fprintf(prompt, "\nNo answer within %v; using %q.\n", opts.confirmTimeout, opts.confirmDefault)
+		return opts.confirmDefault == "y"
+	}
+}
+
+func isYes(response string) bool {
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
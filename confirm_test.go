@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfirmTimeout(t *testing.T) {
+	previousOpts, previousStdin, previousStdout := opts, stdin, stdout
+	defer func() { opts, stdin, stdout = previousOpts, previousStdin, previousStdout }()
+	stdout = io.Discard
+
+	tests := []struct {
+		name     string
+		input    string // empty: nothing is ever typed
+		timeout  time.Duration
+		fallback string
+		want     bool
+	}{
+		{name: "no timeout, yes", input: "y\n", want: true},
+		{name: "no timeout, no", input: "no\n", fallback: "y", want: false},
+		{name: "timeout, default yes", timeout: 20 * time.Millisecond, fallback: "y", want: true},
+		{name: "timeout, default no", timeout: 20 * time.Millisecond, fallback: "n", want: false},
+		{name: "answer before timeout wins", input: "n\n", timeout: time.Minute, fallback: "y", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts = options{confirmMessage: "Proceed?", confirmTimeout: tt.timeout, confirmDefault: tt.fallback}
+			if tt.input != "" {
+				stdin = strings.NewReader(tt.input)
+			} else {
+				// A pipe nobody writes to blocks like an idle terminal.
+				reader, writer := io.Pipe()
+				defer writer.Close()
+				stdin = reader
+			}
+
+			if got := confirmChanges(); got != tt.want {
+				t.Errorf("confirmChanges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfirmPromptWhileOutputHeld(t *testing.T) {
+	previousOpts, previousStdin, previousStdout, previousStderr := opts, stdin, stdout, stderr
+	defer func() { opts, stdin, stdout, stderr = previousOpts, previousStdin, previousStdout, previousStderr }()
+
+	var out, errOut bytes.Buffer
+	stdout, stderr = &out, &errOut
+	opts = options{confirmMessage: "Proceed?", confirmDefault: "n", jsonOutput: true}
+	stdin = strings.NewReader("n\n")
+	holdOutput()
+	defer discardOutput()
+
+	if confirmChanges() {
+		t.Errorf("confirmChanges() = true; want false")
+	}
+	if !strings.Contains(errOut.String(), "Proceed? (y/n): ") {
+		t.Errorf("prompt was not written to stderr while stdout is held; stderr: %q", errOut.String())
+	}
+	if out.Len() != 0 || quietBuffer.Len() != 0 {
+		t.Errorf("prompt went to stdout: %q / held %q", out.String(), quietBuffer.String())
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import "path/filepath"
+
+// With --debug <file>, every import statement in that file is logged along
+// with each pattern tried against it and what, if anything, it matched. Other
+// files are not logged, so the output stays readable.
+func debugTarget(filePath string) bool {
+	if opts.debug == "" {
+		return false
+	}
+	want, err := filepath.Abs(opts.debug)
+	if err != nil {
+		return false
+	}
+	got, err := filepath.Abs(filePath)
+	if err != nil {
+		return false
+	}
+	return want == got
+}
+
+func debugf(filePath, format string, args ...any) {
+	logf(filePath, "debug: "+format+"\n", args...)
+}
+
+func debugPattern(filePath, kind, pattern, match string, matched bool) {
+	if matched {
+		debugf(filePath, "  %s pattern %s matched %q", kind, pattern, match)
+	} else {
+		debugf(filePath, "  %s pattern %s did not match", kind, pattern)
+	}
+}
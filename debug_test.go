@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDebugPatterns(t *testing.T) {
+	var output bytes.Buffer
+	original := stdout
+	stdout = &output
+	defer func() { stdout = original }()
+
+	globalRenames = map[string]string{"Button": "button"}
+	defer func() { globalRenames = make(map[string]string) }()
+	opts = options{debug: "App.vue"}
+	defer func() { opts = options{} }()
+
+	content := `import { Button } from '@/components/ui/Button'
+import Card from './Card.vue'`
+	rewriteContent("App.vue", content)
+
+	logged := output.String()
+	for _, want := range []string{
+		`debug: App.vue: statement "import { Button } from '@/components/ui/Button'"`,
+		`debug:   string pattern from '@/components/ui/Button' matched "from '@/components/ui/Button'"`,
+		`debug: App.vue: statement "import Card from './Card.vue'"`,
+		`debug:   string pattern from '@/components/ui/Button/Button' did not match`,
+	} {
+		if !strings.Contains(logged, want) {
+			t.Errorf("debug output missing %q\n\nGot:\n%s", want, logged)
+		}
+	}
+
+	output.Reset()
+	rewriteContent("Other.vue", content)
+	if strings.Contains(output.String(), "debug:") {
+		t.Errorf("non-target file logged debug output:\n%s", output.String())
+	}
+}
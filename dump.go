@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// dumpTokens is --dump-ast: it prints how the tool reads a file, token by
+// token, then the import statements found outside comments and the
+// components detected, to diagnose imports that are not picked up.
+func dumpTokens(w io.Writer, filePath string) error {
+	raw, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", filePath, err)
+	}
+	content := strings.TrimPrefix(string(raw), utf8BOM)
+	lineStarts := []int{0}
+	for i, c := range content {
+		if c == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	position := func(offset int) string {
+		line := sort.Search(len(lineStarts), func(i int) bool { return lineStarts[i] > offset })
+		return fmt.Sprintf("%d:%d", line, offset-lineStarts[line-1]+1)
+	}
+
+	fmt.Fprintf(w, "Tokens in %s:\n", filePath)
+	for _, t := range tokenize(content) {
+		fmt.Fprintf(w, "  %-8s %-8s %s\n", position(t.start), t.kind, dumpText(content[t.start:t.end]))
+	}
+
+	fmt.Fprintln(w, "Import statements:")
+	spans := commentSpans(content)
+	for _, match := range importStatementRegex.FindAllStringIndex(content, -1) {
+		if !inComment(spans, match[0]) {
+			fmt.Fprintf(w, "  %-8s %s\n", position(match[0]), dumpText(content[match[0]:match[1]]))
+		}
+	}
+
+	fmt.Fprintf(w, "Detected components: %s\n", strings.Join(findComponentImports(content), ", "))
+	return nil
+}
+
+// dumpText quotes a token, cutting long ones so minified code stays readable.
+func dumpText(s string) string {
+	const limit = 80
+	if len(s) > limit {
+		return fmt.Sprintf("%q... (%d bytes)", s[:limit], len(s))
+	}
+	return fmt.Sprintf("%q", s)
+}
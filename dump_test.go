@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	content := "import A from './A.vue' // note\n/* b */const u = 'https://x.dev'\n"
+	var got []string
+	for _, tok := range tokenize(content) {
+		got = append(got, tok.kind+" "+content[tok.start:tok.end])
+	}
+	expected := []string{
+		"code import A from ",
+		"string './A.vue'",
+		"code  ",
+		"comment // note",
+		"code \n",
+		"comment /* b */",
+		"code const u = ",
+		"string 'https://x.dev'",
+		"code \n",
+	}
+	if !slices.Equal(got, expected) {
+		t.Errorf("tokenize() =\n%q\nwant\n%q", got, expected)
+	}
+
+	// The comment regexes this replaced cut a line at the // of a URL.
+	minified := `const u='https://x.dev';import Button from './Button.vue'`
+	if result := findPascalCaseImports(minified); !slices.Equal(result, []string{"Button"}) {
+		t.Errorf("findPascalCaseImports() after a URL = %v; want [Button]", result)
+	}
+}
+
+func TestDumpTokens(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	path := filepath.Join(tmpDir, "App.ts")
+	content := "// import Card from './Card.vue'\nimport Button from './Button.vue'\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := dumpTokens(&out, path); err != nil {
+		t.Fatalf("dumpTokens failed: %v", err)
+	}
+	expected := "Tokens in " + path + ":\n" +
+		`  1:1      comment  "// import Card from './Card.vue'"` + "\n" +
+		`  1:33     code     "\nimport Button from "` + "\n" +
+		`  2:20     string   "'./Button.vue'"` + "\n" +
+		`  2:34     code     "\n"` + "\n" +
+		"Import statements:\n" +
+		`  2:1      "import Button from './Button.vue'"` + "\n" +
+		"Detected components: Button\n"
+	if out.String() != expected {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s", expected, out.String())
+	}
+}
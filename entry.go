@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var entryImportRegex = regexp.MustCompile(`(?:from|import|import\s*\()\s*['"]([^'"\n]+)['"]`)
+
+// buildEntryRenameMap is --entry: instead of scanning dir, detection starts
+// at the files matching pattern and follows their imports, so only the
+// components the app can actually reach are renamed.
+func buildEntryRenameMap(dir, pattern string) error {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	entries, err := filepath.Glob(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid --entry pattern %s: %v", pattern, err)
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no files match --entry %s", pattern)
+	}
+
+	visited := make(map[string]bool)
+	var queue []string
+	for _, entry := range entries {
+		abs, err := filepath.Abs(entry)
+		if err != nil {
+			return err
+		}
+		queue = append(queue, abs)
+	}
+
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		if visited[path] {
+			continue
+		}
+		visited[path] = true
+
+		content, err := os.ReadFile(path)
+		tracef("read %s (err: %v)", path, err)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %v", path, err)
+		}
+		code := stripComments(strings.TrimPrefix(string(content), utf8BOM))
+		for _, name := range findComponentImports(code) {
+			if newName, ok := addDetectedRename(name); ok {
+				fmt.Fprintf(stdout, "Found PascalCase import to rename: %s -> %s in %s\n", name, newName, displayPath(path))
+			}
+		}
+		for _, match := range entryImportRegex.FindAllStringSubmatch(code, -1) {
+			if target, ok := resolveEntryImport(root, filepath.Dir(path), match[1]); ok && !visited[target] {
+				queue = append(queue, target)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveEntryImport finds the file an import in an entry's import graph
+// points at. Relative specifiers resolve from the importing file, aliased
+// ones through the --aliases table, and any other specifier that names the
+// components folder, such as @/components/ui/Button, from root. Packages
+// are not followed.
+func resolveEntryImport(root, dir, specifier string) (string, bool) {
+	if strings.HasPrefix(specifier, "./") || strings.HasPrefix(specifier, "../") {
+		return resolveReexport(dir, specifier)
+	}
+	for _, mapping := range aliasMappings {
+		rest, ok := strings.CutPrefix(specifier, mapping.alias+"/")
+		if !ok {
+			continue
+		}
+		for _, aliasDir := range mapping.dirs {
+			if target, ok := resolveReexport(aliasDir, rest); ok {
+				return target, true
+			}
+		}
+	}
+	marker := "/" + filepath.Base(root) + "/"
+	if i := strings.LastIndex(specifier, marker); i >= 0 {
+		return resolveReexport(root, specifier[i+len(marker):])
+	}
+	return "", false
+}
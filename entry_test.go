@@ -0,0 +1,46 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestBuildEntryRenameMap(t *testing.T) {
+	defer func() { globalRenames = make(map[string]string) }()
+
+	files := map[string]string{
+		"src/App.vue": `import { Button } from '@/components/ui/Button'
+import Layout from './Layout.vue'
+import { ref } from 'vue'`,
+		"src/Layout.vue": `// import Badge from './components/ui/Badge/Badge.vue'
+import Dialog from './components/ui/Dialog/Dialog.vue'`,
+		"src/components/ui/Button/index.ts":   `export { default as Button } from './Button.vue'`,
+		"src/components/ui/Button/Button.vue": `export default {}`,
+		"src/components/ui/Dialog/Dialog.vue": `import Sheet from '../Sheet/Sheet.vue'`,
+		"src/components/ui/Sheet/Sheet.vue":   `export default {}`,
+		"src/components/ui/Card/Card.vue":     `import Badge from '../Badge/Badge.vue'`,
+		"src/components/ui/Badge/Badge.vue":   `export default {}`,
+		"src/pages/Unused.vue":                `import Tooltip from '../components/ui/Tooltip.vue'`,
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = make(map[string]string)
+	ui := filepath.Join(tmpDir, "src", "components", "ui")
+	if err := buildEntryRenameMap(ui, filepath.Join(tmpDir, "src", "*.vue")); err != nil {
+		t.Fatalf("buildEntryRenameMap failed: %v", err)
+	}
+
+	var names []string
+	for name := range globalRenames {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	if want := []string{"Button", "Dialog", "Sheet"}; !slices.Equal(names, want) {
+		t.Errorf("detected %v; want %v", names, want)
+	}
+
+	if err := buildEntryRenameMap(ui, filepath.Join(tmpDir, "missing", "*.vue")); err == nil {
+		t.Error("buildEntryRenameMap with no matching entries succeeded; want an error")
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// escapedImportRegex matches import statements whose specifier quotes are
+// backslash-escaped, as in code stringified into a JS or JSON string:
+// import Button from \'./Button.vue\'. Only the quotes around the specifier
+// may be escaped; the specifier itself has no quotes or backslashes.
+var escapedImportRegex = regexp.MustCompile(
+	`\b(?:import|export)\b[^;'"` + "`" + `\\]*?\bfrom\s*` + escapedSpecifier +
+		`|\bimport\s*\(?\s*` + escapedSpecifier)
+
+const escapedSpecifier = `(?:\\'[^'"\\\n]*\\'|\\"[^'"\\\n]*\\")`
+
+// unescapeImport drops the backslashes before a statement's specifier quotes.
+func unescapeImport(statement string) string {
+	closing := len(statement) - 1
+	opening := lastEscapedQuote(statement[:closing-1], statement[closing])
+	return statement[:opening] + statement[opening+1:closing-1] + statement[closing:]
+}
+
+// escapeImport puts the backslashes unescapeImport dropped back.
+func escapeImport(statement string) string {
+	closing := len(statement) - 1
+	opening := strings.LastIndexByte(statement[:closing], statement[closing])
+	return statement[:opening] + `\` + statement[opening:closing] + `\` + statement[closing:]
+}
+
+func lastEscapedQuote(s string, quote byte) int {
+	for i := len(s) - 1; i > 0; i-- {
+		if s[i] == quote && s[i-1] == '\\' {
+			return i - 1
+		}
+	}
+	return -1
+}
+
+// unescapeImports rewrites every escaped import in content to its plain
+// form, so detection sees it as it would any other import.
+func unescapeImports(content string) string {
+	return escapedImportRegex.ReplaceAllStringFunc(content, unescapeImport)
+}
+
+// rewriteEscapedImports rewrites escaped imports like any other and escapes
+// their quotes again. --quote is not applied, as changing the quote could
+// end the string the statement sits in.
+func rewriteEscapedImports(filePath, content string) string {
+	return replaceOutsideComments(escapedImportRegex, content, func(statement string) string {
+		unescaped := unescapeImport(statement)
+		rewritten := rewriteImportStatement(filePath, unescaped)
+		if rewritten == unescaped {
+			return statement
+		}
+		return escapeImport(rewritten)
+	})
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// processFailures collects the file updates and renames processFiles could
+// not make. They do not stop the run, so every path that can be migrated is,
+// and the run ends with a summary of what failed and what succeeded.
+var processFailures []error
+
+func recordFailure(path string, err error) {
+	processFailures = append(processFailures, fmt.Errorf("%s: %v", displayPath(path), err))
+	fmt.Fprintf(stdout, "Error: %s: %v\n", displayPath(path), err)
+}
+
+func printFailureSummary(w io.Writer) {
+	fmt.Fprintf(w, "\nCompleted with %d failure(s):\n", len(processFailures))
+	for _, err := range processFailures {
+		fmt.Fprintf(w, "  %v\n", err)
+	}
+	fmt.Fprintf(w, "\nSucceeded: %d file(s) rewritten, %d path(s) renamed\n", len(rewrittenFiles), len(renamedPaths))
+	for _, rename := range renamedPaths {
+		fmt.Fprintf(w, "  %s -> %s\n", displayPath(rename.From), displayPath(rename.To))
+	}
+	fmt.Fprintln(w, "\nFix the failures above, then re-run with --resume to finish the migration.")
+}
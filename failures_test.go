@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPartialFailureContinues(t *testing.T) {
+	previous := stdout
+	stdout = io.Discard
+	defer func() { stdout = previous }()
+	defer func() {
+		globalRenames = make(map[string]string)
+		processFailures = nil
+		rewrittenFiles = make(map[string]bool)
+		changedFiles = make(map[string]bool)
+		renamedPaths = nil
+	}()
+
+	files := map[string]string{
+		"Button.vue": `import Card from './Card.vue'`,
+		"Card.vue":   `export default {}`,
+		"Toggle.vue": `export default {}`,
+		// A non-empty directory where card.vue should go makes that one
+		// rename fail midway through the run.
+		"card.vue/keep": ``,
+	}
+	tmpDir := writeTree(t, files)
+
+	rewrittenFiles = make(map[string]bool)
+	renamedPaths = nil
+	globalRenames = map[string]string{"Button": "button", "Card": "card", "Toggle": "toggle"}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	if len(processFailures) != 1 || !strings.Contains(processFailures[0].Error(), "Card.vue") {
+		t.Fatalf("processFailures = %v; want one failure for Card.vue", processFailures)
+	}
+	for _, name := range []string{"button.vue", "toggle.vue", "Card.vue"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, name)); err != nil {
+			t.Errorf("expected %s to exist: %v", name, err)
+		}
+	}
+
+	var out bytes.Buffer
+	printFailureSummary(&out)
+	for _, want := range []string{"Completed with 1 failure(s)", "Card.vue", "Succeeded: 1 file(s) rewritten, 2 path(s) renamed", "toggle.vue"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("summary missing %q:\n%s", want, out.String())
+		}
+	}
+}
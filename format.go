@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	changedFiles   = make(map[string]bool)
+	rewrittenFiles = make(map[string]bool)
+	renamedPaths   []pathRename
+	changesMu      sync.Mutex
+)
+
+type pathRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func recordChange(path string) {
+	changesMu.Lock()
+	defer changesMu.Unlock()
+	changedFiles[path] = true
+	rewrittenFiles[path] = true
+}
+
+func movePaths(set map[string]bool, oldPath, newPath string) {
+	var moved []string
+	for path := range set {
+		if path == oldPath || strings.HasPrefix(path, oldPath+string(filepath.Separator)) {
+			moved = append(moved, path)
+		}
+	}
+	for _, path := range moved {
+		delete(set, path)
+		set[newPath+strings.TrimPrefix(path, oldPath)] = true
+	}
+}
+
+func recordRename(oldPath, newPath string) {
+	movePaths(changedFiles, oldPath, newPath)
+	movePaths(rewrittenFiles, oldPath, newPath)
+	renamedPaths = append(renamedPaths, pathRename{From: oldPath, To: newPath})
+	if info, err := os.Stat(newPath); err == nil && !info.IsDir() {
+		changedFiles[newPath] = true
+	}
+}
+
+func sortedPaths(set map[string]bool) []string {
+	files := make([]string, 0, len(set))
+	for path := range set {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func changedFileList() []string {
+	return sortedPaths(changedFiles)
+}
+
+// runFormatter runs command through sh, so it may quote its arguments, with
+// the changed files passed as "$@" and never split on spaces.
+func runFormatter(command string, files []string) error {
+	if strings.TrimSpace(command) == "" {
+		return fmt.Errorf("empty format command")
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(stdout, "No files changed, skipping formatter.")
+		return nil
+	}
+
+	fmt.Fprintf(stdout, "\nRunning formatter on %d file(s): %s\n", len(files), command)
+	cmd := exec.Command("sh", append([]string{"-c", command + ` "$@"`, "sh"}, files...)...)
+	output, err := cmd.CombinedOutput()
+	if len(output) > 0 {
+		fmt.Fprint(stdout, string(output))
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		fmt.Fprintf(stdout, "Formatter exited with code %d\n", exitErr.ExitCode())
+		return fmt.Errorf("formatter exited with code %d", exitErr.ExitCode())
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, "Formatter exited with code 0")
+	return nil
+}
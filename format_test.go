@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChangedFilesFollowRenames(t *testing.T) {
+	changedFiles = make(map[string]bool)
+	defer func() { changedFiles = make(map[string]bool) }()
+
+	tmpDir := t.TempDir()
+
+	dialogDir := filepath.Join(tmpDir, "Dialog")
+	if err := os.MkdirAll(dialogDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	for _, name := range []string{"Dialog.vue", "index.ts", "Untouched.vue"} {
+		if err := os.WriteFile(filepath.Join(dialogDir, name), []byte(""), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	recordChange(filepath.Join(dialogDir, "index.ts"))
+	if err := renamePath(filepath.Join(dialogDir, "Dialog.vue"), filepath.Join(dialogDir, "dialog.vue")); err != nil {
+		t.Fatalf("renamePath failed: %v", err)
+	}
+	if err := renamePath(dialogDir, filepath.Join(tmpDir, "dialog")); err != nil {
+		t.Fatalf("renamePath failed: %v", err)
+	}
+
+	expected := []string{
+		filepath.Join(tmpDir, "dialog", "dialog.vue"),
+		filepath.Join(tmpDir, "dialog", "index.ts"),
+	}
+	result := changedFileList()
+	if len(result) != len(expected) {
+		t.Fatalf("changedFileList() = %v; want %v", result, expected)
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("changedFileList() = %v; want %v", result, expected)
+			break
+		}
+	}
+}
+
+func TestRunFormatterFailure(t *testing.T) {
+	if err := runFormatter("false", []string{"button.vue"}); err == nil {
+		t.Errorf("runFormatter(%q) succeeded; want exit code error", "false")
+	}
+	if err := runFormatter("true", []string{"button.vue"}); err != nil {
+		t.Errorf("runFormatter(%q) failed: %v", "true", err)
+	}
+}
+
+func TestRunFormatterQuoting(t *testing.T) {
+	var output bytes.Buffer
+	previous := stdout
+	stdout = &output
+	defer func() { stdout = previous }()
+
+	files := []string{"my components/button.vue", "card.vue"}
+	if err := runFormatter(`printf '<%s>' --config "prettier rc.json"`, files); err != nil {
+		t.Fatalf("runFormatter failed: %v", err)
+	}
+	want := "<--config><prettier rc.json><my components/button.vue><card.vue>"
+	if !bytes.Contains(output.Bytes(), []byte(want)) {
+		t.Errorf("formatter arguments were split wrongly; want %s in:\n%s", want, output.String())
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+var runGit = func(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	return cmd.Output()
+}
+
+// fileFilter limits content rewrites and file renames to the listed absolute
+// paths. A nil filter means every file is in scope.
+var fileFilter map[string]bool
+
+func inFileFilter(path string) bool {
+	if fileFilter == nil {
+		return true
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	if resolved, err := filepath.EvalSymlinks(absPath); err == nil {
+		absPath = resolved
+	}
+	return fileFilter[absPath]
+}
+
+func changedSince(ref, dir string) (map[string]bool, error) {
+	topLevel, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("--since requires %s to be inside a git repository", dir)
+	}
+	root := strings.TrimSpace(string(topLevel))
+
+	output, err := runGit(dir, "diff", "--name-only", ref)
+	if err != nil {
+		return nil, fmt.Errorf("error running git diff against %s: %v", ref, err)
+	}
+
+	changed := make(map[string]bool)
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		changed[filepath.Join(root, filepath.FromSlash(line))] = true
+	}
+	return changed, nil
+}
+
+func gitMove(oldPath, newPath string) error {
+	absOld, err := filepath.Abs(oldPath)
+	if err != nil {
+		return err
+	}
+	absNew, err := filepath.Abs(newPath)
+	if err != nil {
+		return err
+	}
+	_, err = runGit(filepath.Dir(absOld), "mv", absOld, absNew)
+	return err
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func initGitRepo(t *testing.T, dir string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "test"},
+	} {
+		if output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+}
+
+func gitCommitAll(t *testing.T, dir string) {
+	t.Helper()
+	for _, args := range [][]string{
+		{"add", "-A"},
+		{"commit", "-q", "-m", "fixture"},
+	} {
+		if output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+}
+
+func TestChangedSinceOutsideRepo(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	_, err := changedSince("origin/main", tmpDir)
+	if err == nil || !strings.Contains(err.Error(), "git repository") {
+		t.Errorf("changedSince() error = %v; want not-a-git-repository error", err)
+	}
+}
+
+func TestSinceLimitsEditsToChangedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	initGitRepo(t, tmpDir)
+
+	files := map[string]string{
+		"Button.vue": `export default {}`,
+		"Card.vue":   `export default {}`,
+		"Dialog.vue": `export default {}`,
+		"Page.vue":   `import Dialog from './Dialog.vue'`,
+	}
+	writeFiles(t, tmpDir, files)
+	gitCommitAll(t, tmpDir)
+
+	// Card.vue and the Button.vue it imports both change, so the run can
+	// rename Button.vue along with the import of it.
+	writeFiles(t, tmpDir, map[string]string{
+		"Button.vue": "// touched\nexport default {}",
+		"Card.vue":   `import Button from './Button.vue'`,
+	})
+
+	var err error
+	fileFilter, err = changedSince("HEAD", tmpDir)
+	if err != nil {
+		t.Fatalf("changedSince failed: %v", err)
+	}
+	defer func() { fileFilter = nil }()
+
+	globalRenames = make(map[string]string)
+	defer func() { globalRenames = make(map[string]string) }()
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if _, exists := globalRenames["Dialog"]; !exists {
+		t.Fatalf("rename map should still be built from the full directory: %v", globalRenames)
+	}
+
+	unresolved, err := verifyPlan(tmpDir)
+	if err != nil {
+		t.Fatalf("verifyPlan failed: %v", err)
+	}
+	if len(unresolved) != 0 {
+		t.Fatalf("verifyPlan = %v; want every import to resolve", unresolved)
+	}
+
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"Card.vue":   `import Button from './button.vue'`,
+		"button.vue": "// touched\nexport default {}",
+		"Dialog.vue": files["Dialog.vue"],
+		"Page.vue":   files["Page.vue"],
+	}
+	for path, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tmpDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", path, want, content)
+		}
+	}
+}
+
+// TestSinceFindsUnresolvedImports covers the trees a --since run refuses:
+// a changed file importing an unchanged component, and an unchanged file
+// importing a changed one.
+func TestSinceFindsUnresolvedImports(t *testing.T) {
+	tmpDir := t.TempDir()
+	initGitRepo(t, tmpDir)
+
+	files := map[string]string{
+		"Button.vue": `import Card from './Card.vue'
+export default {}`,
+		"Card.vue": `export default {}`,
+	}
+	writeFiles(t, tmpDir, files)
+	gitCommitAll(t, tmpDir)
+
+	writeFiles(t, tmpDir, map[string]string{
+		"Card.vue": `import Button from './Button.vue'
+export default {}`,
+	})
+
+	var err error
+	fileFilter, err = changedSince("HEAD", tmpDir)
+	if err != nil {
+		t.Fatalf("changedSince failed: %v", err)
+	}
+	defer func() { fileFilter = nil }()
+
+	globalRenames = make(map[string]string)
+	defer func() { globalRenames = make(map[string]string) }()
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+
+	unresolved, err := verifyPlan(tmpDir)
+	if err != nil {
+		t.Fatalf("verifyPlan failed: %v", err)
+	}
+	var got []string
+	for _, u := range unresolved {
+		got = append(got, filepath.Base(u.file)+": "+u.specifier)
+	}
+	want := []string{"Button.vue: ./Card.vue", "card.vue: ./button.vue"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unresolved = %q; want %q", got, want)
+	}
+}
+
+// TestSinceFailsClosed checks that the --since guard refuses an import it
+// would rewrite but cannot resolve, instead of letting it through unchecked.
+func TestSinceFailsClosed(t *testing.T) {
+	files := map[string]string{
+		"Home.vue":   `import Button from '@/components/ui/Button/Button.vue'`,
+		"Button.vue": `export default {}`,
+	}
+	tmpDir := writeTree(t, files)
+
+	homePath, err := filepath.EvalSymlinks(filepath.Join(tmpDir, "Home.vue"))
+	if err != nil {
+		t.Fatalf("Failed to resolve path: %v", err)
+	}
+	fileFilter = map[string]bool{homePath: true}
+	defer func() { fileFilter = nil }()
+	globalRenames = map[string]string{"Button": "button"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	// No ui folder is under tmpDir, so the ui import cannot be resolved.
+	unresolved, err := verifyPlan(tmpDir)
+	if err != nil {
+		t.Fatalf("verifyPlan failed: %v", err)
+	}
+	if len(unresolved) != 1 || unresolved[0].specifier != "@/components/ui/button/button.vue" {
+		t.Errorf("unresolved = %v; want the unresolvable rewritten import", unresolved)
+	}
+}
+
+func TestUseGitMvInvokesGit(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	oldPath := filepath.Join(tmpDir, "Button.vue")
+	newPath := filepath.Join(tmpDir, "button.vue")
+	if err := os.WriteFile(oldPath, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	var calls [][]string
+	originalRunGit := runGit
+	runGit = func(dir string, args ...string) ([]byte, error) {
+		calls = append(calls, args)
+		return nil, os.Rename(args[1], args[2])
+	}
+	opts = options{useGitMv: true}
+	defer func() {
+		runGit = originalRunGit
+		opts = options{}
+	}()
+
+	if err := renamePath(oldPath, newPath); err != nil {
+		t.Fatalf("renamePath failed: %v", err)
+	}
+
+	if len(calls) != 1 || len(calls[0]) != 3 || calls[0][0] != "mv" || calls[0][1] != oldPath || calls[0][2] != newPath {
+		t.Errorf("git calls = %v; want [[mv %s %s]]", calls, oldPath, newPath)
+	}
+	if _, err := os.Stat(newPath); err != nil {
+		t.Errorf("file was not renamed: %v", err)
+	}
+}
+
+func TestUseGitMvFallsBackToRename(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	oldPath := filepath.Join(tmpDir, "Button.vue")
+	newPath := filepath.Join(tmpDir, "button.vue")
+	if err := os.WriteFile(oldPath, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	originalRunGit := runGit
+	runGit = func(dir string, args ...string) ([]byte, error) {
+		return nil, errors.New("fatal: not under version control")
+	}
+	opts = options{useGitMv: true}
+	defer func() {
+		runGit = originalRunGit
+		opts = options{}
+	}()
+
+	if err := renamePath(oldPath, newPath); err != nil {
+		t.Fatalf("renamePath failed: %v", err)
+	}
+	if _, err := os.Stat(newPath); err != nil {
+		t.Errorf("file was not renamed by the fallback: %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// inventoryComponent is one component the inventory subcommand lists: a
+// component file, whether its name is already kebab-case, and the other
+// components in its folder when the folder is named after it, as with
+// dialog/Dialog.vue and dialog/DialogContent.vue.
+type inventoryComponent struct {
+	Name          string   `json:"name"`
+	Path          string   `json:"path"`
+	Kebab         bool     `json:"kebab"`
+	Subcomponents []string `json:"subcomponents"`
+}
+
+// buildInventory runs the same detection as a rename and lists every
+// component file under dir, in its PascalCase or already kebab-case form.
+func buildInventory(dir string) ([]inventoryComponent, error) {
+	log := stdout
+	stdout = io.Discard
+	err := buildRenameMap(dir)
+	stdout = log
+	if err != nil {
+		return nil, err
+	}
+
+	byDir := make(map[string][]inventoryComponent)
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := filepath.Ext(d.Name())
+		if d.IsDir() || !slices.Contains(componentExtensions, ext) {
+			return nil
+		}
+		if c, ok := inventoryEntry(strings.TrimSuffix(d.Name(), ext)); ok {
+			c.Path = displayPath(path)
+			byDir[filepath.Dir(path)] = append(byDir[filepath.Dir(path)], c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	components := []inventoryComponent{}
+	for folder, inFolder := range byDir {
+		folderComponent, _ := inventoryEntry(filepath.Base(folder))
+		own := slices.IndexFunc(inFolder, func(c inventoryComponent) bool { return c.Name == folderComponent.Name })
+		if own < 0 {
+			components = append(components, inFolder...)
+			continue
+		}
+		for i, c := range inFolder {
+			if i != own {
+				inFolder[own].Subcomponents = append(inFolder[own].Subcomponents, c.Name)
+			}
+		}
+		sort.Strings(inFolder[own].Subcomponents)
+		components = append(components, inFolder[own])
+	}
+	sort.Slice(components, func(i, j int) bool { return components[i].Path < components[j].Path })
+	return components, nil
+}
+
+// inventoryEntry reports whether name is a component, either as detected or
+// named after a known prefix, or in the kebab-case a rename gives it.
+func inventoryEntry(name string) (inventoryComponent, bool) {
+	if _, ok := globalRenames[name]; ok || isComponentName(name) {
+		return inventoryComponent{Name: name, Subcomponents: []string{}}, true
+	}
+	pascal := joinPascal(strings.Split(name, "-"))
+	if name == toKebabCase(pascal) && isPascalCase(pascal) {
+		return inventoryComponent{Name: pascal, Kebab: true, Subcomponents: []string{}}, true
+	}
+	return inventoryComponent{}, false
+}
+
+func printInventory(w io.Writer, components []inventoryComponent) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "COMPONENT\tPATH\tKEBAB\tSUB-COMPONENTS")
+	for _, c := range components {
+		kebab := "no"
+		if c.Kebab {
+			kebab = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Name, c.Path, kebab, strings.Join(c.Subcomponents, ", "))
+	}
+	tw.Flush()
+}
+
+// runInventory is the inventory subcommand: it lists the components in a
+// tree as a table or JSON without planning or making any change.
+func runInventory(args []string) int {
+	fs := flag.NewFlagSet("rename-shadcn-vue inventory", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	jsonOutput := fs.Bool("json", false, "print the inventory as JSON instead of a table")
+	fs.Var(&opts.prefixes, "prefix", "additional component prefix to recognize (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	componentPrefixes = append(componentPrefixes, opts.prefixes...)
+
+	var dir string
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+		if err := validateComponentsDir(dir); err != nil {
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			return 1
+		}
+	} else {
+		var err error
+		if dir, err = findComponentsDir(); err != nil {
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			fmt.Fprintln(stdout, "Usage: rename-shadcn-vue inventory [flags] [components_directory]")
+			return 1
+		}
+	}
+	pathBase = dir
+
+	components, err := buildInventory(dir)
+	if err != nil {
+		fmt.Fprintf(stdout, "Error building inventory: %v\n", err)
+		return 1
+	}
+	if *jsonOutput {
+		content, err := json.MarshalIndent(components, "", "  ")
+		if err != nil {
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Fprintln(stdout, string(content))
+		return 0
+	}
+	printInventory(stdout, components)
+	return 0
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInventory(t *testing.T) {
+	defer func() {
+		globalRenames = make(map[string]string)
+		pathBase = ""
+	}()
+
+	files := map[string]string{
+		"Dialog/Dialog.vue":        `export default {}`,
+		"Dialog/DialogContent.vue": `export default {}`,
+		"Dialog/index.ts":          `export { default as Dialog } from './Dialog.vue'`,
+		"button/button.vue":        `export default {}`,
+		"button/button-group.vue":  `export default {}`,
+		"Card.vue":                 `<script setup>import { Button } from './button'</script>`,
+		"Helper.vue":               `export default {}`,
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = make(map[string]string)
+	pathBase = tmpDir
+	components, err := buildInventory(tmpDir)
+	if err != nil {
+		t.Fatalf("buildInventory failed: %v", err)
+	}
+
+	want := []inventoryComponent{
+		{Name: "Card", Path: "Card.vue", Subcomponents: []string{}},
+		{Name: "Dialog", Path: filepath.Join("Dialog", "Dialog.vue"), Subcomponents: []string{"DialogContent"}},
+		{Name: "Button", Path: filepath.Join("button", "button.vue"), Kebab: true, Subcomponents: []string{"ButtonGroup"}},
+	}
+	if !reflect.DeepEqual(components, want) {
+		t.Errorf("buildInventory() = %+v, want %+v", components, want)
+	}
+
+	var table bytes.Buffer
+	printInventory(&table, components)
+	if !strings.Contains(table.String(), "Button     "+filepath.Join("button", "button.vue")+"  yes    ButtonGroup") {
+		t.Errorf("table does not list Button as kebab with its sub-component:\n%s", table.String())
+	}
+	if _, err := json.Marshal(components); err != nil {
+		t.Errorf("inventory does not marshal to JSON: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "Dialog", "Dialog.vue")); err != nil {
+		t.Errorf("inventory changed the tree: %v", err)
+	}
+}
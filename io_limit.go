@@ -0,0 +1,28 @@
+package main
+
+// defaultIOConcurrency keeps a large --parallel on a many-core machine from
+// queueing more file operations than a slow or networked disk serves well.
+const defaultIOConcurrency = 8
+
+// ioSlots is the --io-concurrency semaphore: a file operation holds a slot
+// while it runs. It is nil, and operations are not limited, until main sets
+// it up.
+var ioSlots chan struct{}
+
+func withIOSlot(op func() error) error {
+	if ioSlots != nil {
+		ioSlots <- struct{}{}
+		defer func() { <-ioSlots }()
+	}
+	return op()
+}
+
+func readFileLimited(name string) ([]byte, error) {
+	var content []byte
+	err := withIOSlot(func() error {
+		var err error
+		content, err = fileOps.ReadFile(name)
+		return err
+	})
+	return content, err
+}
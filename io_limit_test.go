@@ -0,0 +1,78 @@
+package main
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingFileSystem records the most file operations it ever had in flight
+// at once, holding each one open for a moment so workers overlap.
+type countingFileSystem struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (c *countingFileSystem) enter() {
+	c.mu.Lock()
+	c.inFlight++
+	c.peak = max(c.peak, c.inFlight)
+	c.mu.Unlock()
+	time.Sleep(2 * time.Millisecond)
+}
+
+func (c *countingFileSystem) leave() {
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+}
+
+func (c *countingFileSystem) ReadFile(name string) ([]byte, error) {
+	c.enter()
+	defer c.leave()
+	return os.ReadFile(name)
+}
+
+func (c *countingFileSystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	c.enter()
+	defer c.leave()
+	return os.WriteFile(name, data, perm)
+}
+
+func (c *countingFileSystem) Rename(oldPath, newPath string) error {
+	c.enter()
+	defer c.leave()
+	return os.Rename(oldPath, newPath)
+}
+
+func TestIOConcurrency(t *testing.T) {
+	globalRenames = map[string]string{"Dialog": "dialog", "DialogContent": "dialog-content", "Button": "button"}
+	counting := &countingFileSystem{}
+	previousStdout := stdout
+	stdout = io.Discard
+	fileOps = counting
+	ioSlots = make(chan struct{}, 2)
+	defer func() {
+		globalRenames = make(map[string]string)
+		stdout = previousStdout
+		fileOps = osFileSystem{}
+		ioSlots = nil
+		contentsRewritten = false
+	}()
+
+	tmpDir := writeParallelFixture(t)
+
+	if err := updateContentsParallel(tmpDir, 8); err != nil {
+		t.Fatalf("updateContentsParallel failed: %v", err)
+	}
+	if counting.peak == 0 {
+		t.Fatal("no file operation went through the file system seam")
+	}
+	if counting.peak > 2 {
+		t.Errorf("%d file operations ran at once with --io-concurrency 2 and 8 workers", counting.peak)
+	}
+}
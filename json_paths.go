@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// rewriteJSONPaths rewrites component paths held as string values in the JSON
+// files matched by the given globs. JSON has no import syntax, so every string
+// leaf that looks like a path is checked segment by segment.
+func rewriteJSONPaths(globs []string) error {
+	for _, glob := range globs {
+		matches, err := filepath.Glob(glob)
+		if err != nil {
+			return fmt.Errorf("invalid --json-paths pattern %q: %v", glob, err)
+		}
+		for _, path := range matches {
+			if err := updateJSONFile(path); err != nil {
+				return fmt.Errorf("error rewriting %s: %v", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+func updateJSONFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("malformed JSON: %v", err)
+	}
+
+	changed := false
+	value = rewriteJSONValue(value, &changed)
+	if !changed {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Updated component paths in: %s\n", displayPath(path))
+	if err := writeFileRetrying(path, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	recordChange(path)
+	return nil
+}
+
+func rewriteJSONValue(value interface{}, changed *bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = rewriteJSONValue(item, changed)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = rewriteJSONValue(item, changed)
+		}
+	case string:
+		if rewritten := rewritePathString(v); rewritten != v {
+			*changed = true
+			return rewritten
+		}
+	}
+	return value
+}
+
+// rewritePathString renames each segment of a slash-separated path that names
+// a renamed component, either as a folder or as a .vue (or sibling) file.
+// Strings without a slash or .vue extension are not treated as paths.
+func rewritePathString(value string) string {
+	if !strings.Contains(value, "/") && !strings.HasSuffix(value, ".vue") {
+		return value
+	}
+
+	segments := strings.Split(value, "/")
+	for i, segment := range segments {
+		if newName, ok := globalRenames[segment]; ok {
+			segments[i] = newName
+			continue
+		}
+		for _, suffix := range append(append([]string{}, componentExtensions...), siblingSuffixes...) {
+			base := strings.TrimSuffix(segment, suffix)
+			if base == segment {
+				continue
+			}
+			if newName, ok := globalRenames[base]; ok {
+				segments[i] = newName + suffix
+				break
+			}
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRewriteJSONPaths(t *testing.T) {
+	globalRenames = map[string]string{"Button": "button", "AlertDialog": "alert-dialog"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	tmpDir := t.TempDir()
+
+	input := `{
+  "stories": ["src/components/ui/Button/Button.stories.ts"],
+  "components": {
+    "alert": "src/components/ui/AlertDialog/AlertDialog.vue",
+    "label": "Button",
+    "order": 1.50
+  }
+}
+`
+	expected := `{
+  "components": {
+    "alert": "src/components/ui/alert-dialog/alert-dialog.vue",
+    "label": "Button",
+    "order": 1.50
+  },
+  "stories": [
+    "src/components/ui/button/button.stories.ts"
+  ]
+}
+`
+	configPath := filepath.Join(tmpDir, "storybook.json")
+	if err := os.WriteFile(configPath, []byte(input), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	if err := rewriteJSONPaths([]string{filepath.Join(tmpDir, "*.json")}); err != nil {
+		t.Fatalf("rewriteJSONPaths failed: %v", err)
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if string(content) != expected {
+		t.Errorf("\nExpected:\n%s\n\nGot:\n%s", expected, content)
+	}
+}
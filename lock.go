@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const lockFileName = ".rename.lock"
+
+// heldLock is the lock file this run created, removed again by releaseLock
+// on every exit path so a finished run never leaves a stale lock behind.
+// lockMu orders the signal handler's release after an acquire in progress.
+var (
+	heldLock string
+	lockMu   sync.Mutex
+)
+
+// acquireLock creates the lock file in dir, failing if another run holds it.
+// With force, an existing lock is taken to be stale and replaced.
+func acquireLock(dir string, force bool) error {
+	lockMu.Lock()
+	defer lockMu.Unlock()
+
+	path := filepath.Join(dir, lockFileName)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if os.IsExist(err) {
+		if !force {
+			holder, _ := os.ReadFile(path)
+			return fmt.Errorf("%s is locked by another run (%s); if no other run is going, delete %s or re-run with --force-lock",
+				dir, strings.TrimSpace(string(holder)), path)
+		}
+		fmt.Fprintf(stdout, "Warning: replacing existing lock %s\n", path)
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("error removing stale lock: %v", err)
+		}
+		file, err = os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	}
+	if err != nil {
+		return fmt.Errorf("error creating lock: %v", err)
+	}
+	defer file.Close()
+	heldLock = path
+	_, err = fmt.Fprintf(file, "pid %d, started %s\n", os.Getpid(), time.Now().Format(time.RFC3339))
+	return err
+}
+
+// releaseLockOnSignal removes the lock when the run is interrupted, e.g. with
+// Ctrl-C at the confirmation prompt, which would otherwise leave it behind
+// and make every later run refuse to start. It is installed before the lock
+// is taken so no signal can slip in between.
+func releaseLockOnSignal() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		cleanup()
+		fmt.Fprintln(os.Stderr, "\nInterrupted.")
+		os.Exit(128 + int(sig.(syscall.Signal)))
+	}()
+}
+
+func releaseLock() {
+	lockMu.Lock()
+	defer lockMu.Unlock()
+	if heldLock == "" {
+		return
+	}
+	os.Remove(heldLock)
+	heldLock = ""
+}
+
+// isToolFile reports whether name is one of the files a run keeps in the
+// components directory, which are never copied, listed or patched.
+func isToolFile(name string) bool {
+	return name == checkpointFileName || name == lockFileName
+}
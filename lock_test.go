@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLockHeldByAnotherRun(t *testing.T) {
+	previous := stdout
+	stdout = io.Discard
+	defer func() { stdout = previous }()
+	defer releaseLock()
+
+	tmpDir := t.TempDir()
+
+	lockPath := filepath.Join(tmpDir, lockFileName)
+	if err := os.WriteFile(lockPath, []byte("pid 4242, started 2026-01-01T00:00:00Z\n"), 0644); err != nil {
+		t.Fatalf("Failed to write lock: %v", err)
+	}
+
+	err := acquireLock(tmpDir, false)
+	if err == nil || !strings.Contains(err.Error(), "pid 4242") {
+		t.Fatalf("acquireLock with a held lock: err = %v; want it to name the holder", err)
+	}
+	if heldLock != "" {
+		t.Errorf("heldLock = %q after a failed acquire", heldLock)
+	}
+	content, err := os.ReadFile(lockPath)
+	if err != nil || !strings.Contains(string(content), "pid 4242") {
+		t.Errorf("held lock was changed: %q, %v", content, err)
+	}
+
+	if err := acquireLock(tmpDir, true); err != nil {
+		t.Fatalf("acquireLock with --force-lock failed: %v", err)
+	}
+	content, err = os.ReadFile(lockPath)
+	if err != nil || strings.Contains(string(content), "pid 4242") {
+		t.Errorf("stale lock was not replaced: %q, %v", content, err)
+	}
+
+	releaseLock()
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("lock still present after releaseLock: %v", err)
+	}
+	if err := acquireLock(tmpDir, false); err != nil {
+		t.Errorf("acquireLock after release failed: %v", err)
+	}
+}
+
+// TestLockReleasedOnInterrupt runs the real main in a child process, waits
+// for it to reach the confirmation prompt holding the lock, and interrupts it.
+func TestLockReleasedOnInterrupt(t *testing.T) {
+	if dir := os.Getenv("RENAME_SHADCN_VUE_LOCK_DIR"); dir != "" {
+		os.Args = append([]string{"rename-shadcn-vue", dir}, strings.Fields(os.Getenv("RENAME_SHADCN_VUE_LOCK_FLAGS"))...)
+		main()
+		os.Exit(0)
+	}
+
+	// With --scope the lock still goes on the components root, so runs on
+	// different scopes of one root exclude each other.
+	for _, scope := range []string{"", filepath.Join("ui", "Button")} {
+		t.Run("scope="+scope, func(t *testing.T) {
+			files := map[string]string{
+				"ui/Button/Button.vue": `export default {}`,
+				"ui/Button/index.ts":   `export { default as Button } from './Button.vue'`,
+			}
+			tmpDir := writeTree(t, files)
+			lockPath := filepath.Join(tmpDir, "ui", lockFileName)
+
+			cmd := exec.Command(os.Args[0], "-test.run=^TestLockReleasedOnInterrupt$")
+			cmd.Env = append(os.Environ(), "RENAME_SHADCN_VUE_LOCK_DIR="+filepath.Join(tmpDir, "ui"))
+			if scope != "" {
+				cmd.Env = append(cmd.Env, "RENAME_SHADCN_VUE_LOCK_FLAGS=--scope "+filepath.Join(tmpDir, scope))
+			}
+			cmd.Dir = tmpDir
+			// An open pipe that is never written to keeps the run at the prompt.
+			stdinPipe, err := cmd.StdinPipe()
+			if err != nil {
+				t.Fatalf("Failed to open stdin pipe: %v", err)
+			}
+			defer stdinPipe.Close()
+			if err := cmd.Start(); err != nil {
+				t.Fatalf("Failed to start child run: %v", err)
+			}
+
+			deadline := time.Now().Add(10 * time.Second)
+			for {
+				if _, err := os.Stat(lockPath); err == nil {
+					break
+				}
+				if time.Now().After(deadline) {
+					cmd.Process.Kill()
+					cmd.Wait()
+					t.Fatalf("child run never took the lock")
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			if err := cmd.Process.Signal(os.Interrupt); err != nil {
+				t.Fatalf("Failed to interrupt child run: %v", err)
+			}
+			err = cmd.Wait()
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) || exitErr.ExitCode() != 130 {
+				t.Errorf("interrupted run: err = %v; want exit code 130", err)
+			}
+			if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+				t.Errorf("lock left behind after interrupt: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(tmpDir, "ui", "Button", "Button.vue")); err != nil {
+				t.Errorf("interrupted run touched disk: %v", err)
+			}
+		})
+	}
+}
+
+// TestDryRunIgnoresLock checks that a read-only run neither takes nor
+// respects the lock of a run in progress.
+func TestDryRunIgnoresLock(t *testing.T) {
+	files := map[string]string{
+		"ui/Button/Button.vue": `export default {}`,
+		"ui/Button/index.ts":   `export { default as Button } from './Button.vue'`,
+		"ui/" + lockFileName:   "pid 4242, started 2026-01-01T00:00:00Z\n",
+	}
+	tmpDir := writeTree(t, files)
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestLockReleasedOnInterrupt$")
+	cmd.Env = append(os.Environ(),
+		"RENAME_SHADCN_VUE_LOCK_DIR="+filepath.Join(tmpDir, "ui"),
+		"RENAME_SHADCN_VUE_LOCK_FLAGS=--dry-run")
+	cmd.Dir = tmpDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("--dry-run with a held lock failed: %v\n%s", err, output)
+	}
+	content, err := os.ReadFile(filepath.Join(tmpDir, "ui", lockFileName))
+	if err != nil || !strings.Contains(string(content), "pid 4242") {
+		t.Errorf("--dry-run changed the held lock: %q, %v", content, err)
+	}
+}
@@ -1,19 +1,90 @@
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 	"unicode"
 )
 
 var globalRenames = make(map[string]string)
 
+var stdout io.Writer = os.Stdout
+
+var componentPrefixes = []string{
+	"Sidebar",
+	"Accordion",
+	"Alert",
+	"AlertDialog",
+	"AspectRatio",
+	"Avatar",
+	"Badge",
+	"Breadcrumb",
+	"Button",
+	"Calendar",
+	"Card",
+	"Carousel",
+	"Checkbox",
+	"Collapsible",
+	"Combobox",
+	"Command",
+	"ContextMenu",
+	"DataTable",
+	"DatePicker",
+	"Dialog",
+	"Drawer",
+	"DropdownMenu",
+	"Form",
+	"HoverCard",
+	"Input",
+	"Label",
+	"Menubar",
+	"NavigationMenu",
+	"NumberField",
+	"Pagination",
+	"PinInput",
+	"Popover",
+	"Progress",
+	"RadioGroup",
+	"RangeCalendar",
+	"Resizable",
+	"ScrollArea",
+	"Select",
+	"Separator",
+	"Sheet",
+	"Skeleton",
+	"Slider",
+	"Sonner",
+	"Stepper",
+	"Switch",
+	"Table",
+	"Tabs",
+	"TagsInput",
+	"Textarea",
+	"Toast",
+	"Toggle",
+	"ToggleGroup",
+	"Tooltip",
+}
+
+// acronyms are kept together as a single word when converting to kebab-case,
+// so OAuthButton becomes oauth-button rather than o-auth-button.
+var acronyms = []string{"UI", "OAuth"}
+
 func toKebabCase(s string) string {
-	s = strings.ReplaceAll(s, "UI", "Ui")
+	for _, acronym := range acronyms {
+		if len(acronym) > 1 {
+			s = replaceAcronym(s, acronym)
+		}
+	}
 
 	var result strings.Builder
 	var prevIsUpper bool
@@ -30,114 +101,127 @@ func toKebabCase(s string) string {
 	return result.String()
 }
 
-func isPascalCase(s string) bool {
-	if strings.HasSuffix(s, "Props") || strings.HasSuffix(s, "Emits") || strings.HasSuffix(s, "Context") {
+// replaceAcronym lower-cases all but the first letter of each occurrence of
+// acronym that is a word of its own, so UI in UIButton is replaced but the UI
+// in GUIDValue or BUILDInfo is not.
+func replaceAcronym(s, acronym string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, acronym)
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		end := i + len(acronym)
+		if acronymBoundary(s, acronym, i, end) {
+			b.WriteString(s[:i] + acronym[:1] + strings.ToLower(acronym[1:]))
+		} else {
+			b.WriteString(s[:i+1])
+			end = i + 1
+		}
+		s = s[end:]
+	}
+}
+
+func acronymBoundary(s, acronym string, start, end int) bool {
+	if start > 0 && unicode.IsUpper(rune(s[start-1])) {
+		return false
+	}
+	if end == len(s) || !unicode.IsLetter(rune(s[end])) {
+		return true
+	}
+	if !unicode.IsUpper(rune(s[end])) {
 		return false
 	}
+	// Another capital starts the next word if the acronym ends in lower
+	// case (OAuthButton) or the capital is followed by lower case (UIButton).
+	return unicode.IsLower(rune(acronym[len(acronym)-1])) ||
+		(end+1 < len(s) && unicode.IsLower(rune(s[end+1])))
+}
+
+func isPascalCase(s string) (pascal bool) {
+	if tracing() {
+		defer func() { tracef("isPascalCase %q = %v", s, pascal) }()
+	}
+	// A single letter is never a component name, even under --prefix X.
+	if len(strings.TrimSpace(s)) < 2 {
+		return false
+	}
+
+	if isNonComponentName(s) || !hasComponentPrefix(s) {
+		return false
+	}
+	return !opts.strictPascal || isCanonicalComponent(s)
+}
+
+// isNonComponentName reports whether s is a type, helper or constant name
+// that is never a component, whatever its prefix.
+func isNonComponentName(s string) bool {
+	if strings.HasSuffix(s, "Props") || strings.HasSuffix(s, "Emits") || strings.HasSuffix(s, "Context") {
+		return true
+	}
 
 	skipWords := []string{"HTML", "Ref", "VModel", "Component", "Primitive", "Variants", "Omit",
 		"NAME", "AGE", "ICON", "WIDTH", "MOBILE", "SHORTCUT", "SOURCE", "Provider", "Portal"}
 	for _, word := range skipWords {
 		if strings.Contains(s, word) {
-			return false
-		}
-	}
-
-	componentPrefixes := []string{
-		"Sidebar",
-		"Accordion",
-		"Alert",
-		"AlertDialog",
-		"AspectRatio",
-		"Avatar",
-		"Badge",
-		"Breadcrumb",
-		"Button",
-		"Calendar",
-		"Card",
-		"Carousel",
-		"Checkbox",
-		"Collapsible",
-		"Combobox",
-		"Command",
-		"ContextMenu",
-		"DataTable",
-		"DatePicker",
-		"Dialog",
-		"Drawer",
-		"DropdownMenu",
-		"Form",
-		"HoverCard",
-		"Input",
-		"Label",
-		"Menubar",
-		"NavigationMenu",
-		"NumberField",
-		"Pagination",
-		"PinInput",
-		"Popover",
-		"Progress",
-		"RadioGroup",
-		"RangeCalendar",
-		"Resizable",
-		"ScrollArea",
-		"Select",
-		"Separator",
-		"Sheet",
-		"Skeleton",
-		"Slider",
-		"Sonner",
-		"Stepper",
-		"Switch",
-		"Table",
-		"Tabs",
-		"TagsInput",
-		"Textarea",
-		"Toast",
-		"Toggle",
-		"ToggleGroup",
-		"Tooltip",
+			return true
+		}
 	}
+	return false
+}
 
+func hasComponentPrefix(s string) bool {
 	for _, prefix := range componentPrefixes {
 		if strings.HasPrefix(s, prefix) {
 			return true
 		}
 	}
-
 	return false
 }
 
+// Go's regexp is RE2, so matching is linear in the input and cannot
+// backtrack catastrophically. The patterns still keep each specifier match
+// inside its quotes ([^'"\n]*), so on a long minified line a match cannot run
+// on into the next statement and a failed match stops at the closing quote.
+var (
+	pascalImportRegexes = compilePatterns([]string{
+		`import\s+([A-Z][a-zA-Z0-9]+)(?:\s*,\s*([A-Z][a-zA-Z0-9]+))*\s+from`,
+		`import\s*{\s*([A-Z][a-zA-Z0-9]+(?:\s*,\s*[A-Z][a-zA-Z0-9]+)*)\s*}\s*from`,
+		`from\s+['"][^'"\n]*/([A-Z][a-zA-Z0-9]+)\.vue\s*['"]`,
+		`from\s+['"][^'"\n]*/([A-Z][a-zA-Z0-9]+)\s*['"]`,
+		`from\s+['"][^'"\n]*/([A-Z][a-zA-Z0-9]+)(?:\.vue)?[?#][^'"]*['"]`,
+		`export\s*{\s*default\s+as\s+([A-Z][a-zA-Z0-9]+)\s*}\s*from\s*['"]`,
+		`export\s*{\s*([A-Z][a-zA-Z0-9]+(?:\s*,\s*[A-Z][a-zA-Z0-9]+)*)\s*}\s*from\s*['"]`,
+		`import\s*{\s*([A-Z][a-zA-Z0-9]+(?:\s*,\s*[A-Z][a-zA-Z0-9]+)*)\s*}\s*from\s*['"][^'"\n]*/[A-Z][a-zA-Z]+['"]`,
+		`import\s*\*\s*as\s+[A-Za-z_$][\w$]*\s+from\s*['"][^'"]*/([A-Z][a-zA-Z0-9]+)(?:\.vue)?['"]`,
+		`import\s*['"][^'"]*/([A-Z][a-zA-Z0-9]+)(?:\.vue)?['"]`,
+		`declare\s+module\s*['"][^'"\n]*/([A-Z][a-zA-Z0-9]+)(?:\.vue)?['"]`,
+	})
+)
+
+func compilePatterns(patterns []string) []*regexp.Regexp {
+	regexes := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		regexes[i] = regexp.MustCompile(pattern)
+	}
+	return regexes
+}
+
 func findPascalCaseImports(content string) []string {
 	found := make(map[string]bool)
 	var results []string
 
-	singleLineCommentRegex := regexp.MustCompile(`//.*$`)
-	lines := strings.Split(content, "\n")
-	var cleanedLines []string
-	for _, line := range lines {
-		cleanedLine := singleLineCommentRegex.ReplaceAllString(line, "")
-		if strings.TrimSpace(cleanedLine) != "" {
-			cleanedLines = append(cleanedLines, cleanedLine)
-		}
-	}
-	cleanContent := strings.Join(cleanedLines, "\n")
-
-	multiLineCommentRegex := regexp.MustCompile(`/\*[\s\S]*?\*/`)
-	cleanContent = multiLineCommentRegex.ReplaceAllString(cleanContent, "")
+	cleanContent := unescapeImports(stripComments(content))
 
-	patterns := []string{
-		`import\s+([A-Z][a-zA-Z0-9]+)(?:\s*,\s*([A-Z][a-zA-Z0-9]+))*\s+from`,
-		`import\s*{\s*([A-Z][a-zA-Z0-9]+(?:\s*,\s*[A-Z][a-zA-Z0-9]+)*)\s*}\s*from`,
-		`from\s+['"].*?/([A-Z][a-zA-Z0-9]+)\.vue['"]`,
-		`from\s+['"].*?/([A-Z][a-zA-Z0-9]+)['"]`,
-		`export\s*{\s*default\s+as\s+([A-Z][a-zA-Z0-9]+)\s*}\s*from\s*['"]`,
-		`export\s*{\s*([A-Z][a-zA-Z0-9]+(?:\s*,\s*[A-Z][a-zA-Z0-9]+)*)\s*}\s*from\s*['"]`,
-		`import\s*{\s*([A-Z][a-zA-Z0-9]+(?:\s*,\s*[A-Z][a-zA-Z0-9]+)*)\s*}\s*from\s*['"].*?/[A-Z][a-zA-Z]+['"]`,
+	regexes := pascalImportRegexes
+	for _, ext := range componentExtensions {
+		if ext != ".vue" {
+			regexes = append(regexes[:len(regexes):len(regexes)], compilePattern(`from\s+['"][^'"\n]*/([A-Z][a-zA-Z0-9]+)`+regexp.QuoteMeta(ext)+`['"]`))
+		}
 	}
 
-	for _, pattern := range patterns {
-		regex := regexp.MustCompile(pattern)
+	for _, regex := range regexes {
 		matches := regex.FindAllStringSubmatch(cleanContent, -1)
 		for _, match := range matches {
 			for i := 1; i < len(match); i++ {
@@ -158,9 +242,59 @@ func findPascalCaseImports(content string) []string {
 		}
 	}
 
+	for _, literal := range templateLiteralRegex.FindAllString(cleanContent, -1) {
+		for _, segment := range templateLiteralSegments(literal) {
+			component := literal[segment[0]:segment[1]]
+			if isPascalCase(component) && !found[component] {
+				found[component] = true
+				results = append(results, component)
+			}
+		}
+	}
+
 	return results
 }
 
+// templateLiteralRegex matches a template literal passed to a dynamic
+// import(); other backtick strings are data and are left alone.
+var (
+	templateLiteralRegex = regexp.MustCompile(`\bimport\s*\(\s*` + "`[^`]*`")
+	templateSegmentRegex = regexp.MustCompile(`/([A-Z][a-zA-Z0-9]*)`)
+)
+
+// Only segments bounded by a slash, a .vue extension or the closing backtick
+// count, so names touching a ${...} interpolation are left alone.
+func templateLiteralSegments(literal string) [][]int {
+	if !strings.Contains(literal, "components/") {
+		return nil
+	}
+
+	var segments [][]int
+	for _, match := range templateSegmentRegex.FindAllStringSubmatchIndex(literal, -1) {
+		rest := literal[match[3]:]
+		if strings.HasPrefix(rest, "/") || strings.HasPrefix(rest, ".vue") || rest == "`" {
+			segments = append(segments, []int{match[2], match[3]})
+		}
+	}
+	return segments
+}
+
+func rewriteTemplateLiterals(content string) string {
+	return replaceOutsideComments(templateLiteralRegex, content, func(literal string) string {
+		segments := templateLiteralSegments(literal)
+		for i := len(segments) - 1; i >= 0; i-- {
+			start, end := segments[i][0], segments[i][1]
+			if opts.dirsOnly && !strings.HasPrefix(literal[end:], "/") {
+				continue
+			}
+			if newName, ok := globalRenames[literal[start:end]]; ok {
+				literal = literal[:start] + newName + literal[end:]
+			}
+		}
+		return literal
+	})
+}
+
 func findComponentsDir() (string, error) {
 	commonPaths := []string{
 		"app/components",
@@ -177,7 +311,7 @@ func findComponentsDir() (string, error) {
 	for _, basePath := range commonPaths {
 		path := filepath.Join(cwd, basePath, "ui")
 		if info, err := os.Stat(path); err == nil && info.IsDir() {
-			fmt.Printf("Found components directory: %s\n", path)
+			fmt.Fprintf(stdout, "Found components directory: %s\n", path)
 			return path, nil
 		}
 	}
@@ -185,7 +319,7 @@ func findComponentsDir() (string, error) {
 	for _, path := range commonPaths {
 		fullPath := filepath.Join(cwd, path)
 		if info, err := os.Stat(fullPath); err == nil && info.IsDir() {
-			fmt.Printf("Found components directory: %s\n", fullPath)
+			fmt.Fprintf(stdout, "Found components directory: %s\n", fullPath)
 			return fullPath, nil
 		}
 	}
@@ -193,6 +327,53 @@ func findComponentsDir() (string, error) {
 	return "", fmt.Errorf("could not find components directory in common locations. Please provide the path as an argument")
 }
 
+func sortedRenameNames() []string {
+	names := make([]string, 0, len(globalRenames))
+	for name := range globalRenames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// longestRenameNames orders names longest first so a family member such as
+// DataTableRow is rewritten before its shorter prefix DataTable.
+func longestRenameNames() []string {
+	names := sortedRenameNames()
+	sort.SliceStable(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	return names
+}
+
+func addRename(oldName, newName string) bool {
+	if strings.TrimSpace(oldName) == "" || strings.TrimSpace(newName) == "" {
+		return false
+	}
+	globalRenames[oldName] = newName
+	return true
+}
+
+func validateComponentsDir(path string) error {
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		return fmt.Errorf("components directory not found: %s", path)
+	case os.IsPermission(err):
+		return fmt.Errorf("permission denied accessing components directory: %s", path)
+	case err != nil:
+		return fmt.Errorf("error accessing components directory %s: %v", path, err)
+	case !info.IsDir():
+		return fmt.Errorf("not a directory: %s (expected the components directory)", path)
+	}
+
+	if _, err := os.ReadDir(path); err != nil {
+		if os.IsPermission(err) {
+			return fmt.Errorf("permission denied reading components directory: %s", path)
+		}
+		return fmt.Errorf("error reading components directory %s: %v", path, err)
+	}
+	return nil
+}
+
 func buildRenameMap(dir string) error {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -200,21 +381,24 @@ func buildRenameMap(dir string) error {
 	}
 
 	for _, f := range entries {
-		if !f.IsDir() && (strings.HasSuffix(f.Name(), ".vue") || strings.HasSuffix(f.Name(), ".ts")) {
+		if !f.IsDir() && isSourceFile(f.Name()) && !isMarkdownFile(f.Name()) {
 			filePath := filepath.Join(dir, f.Name())
 			content, err := os.ReadFile(filePath)
+			tracef("read %s (err: %v)", filePath, err)
 			if err != nil {
 				continue
 			}
 
-			pascalImports := findPascalCaseImports(string(content))
+			pascalImports := findComponentImports(strings.TrimPrefix(string(content), utf8BOM))
+			noteUnrecognizedNames(filePath, string(content))
 			for _, name := range pascalImports {
-				if _, exists := globalRenames[name]; !exists {
-					newName := toKebabCase(name)
-					globalRenames[name] = newName
-					fmt.Printf("Found PascalCase import to rename: %s -> %s in %s\n", name, newName, filePath)
+				if newName, ok := addDetectedRename(name); ok {
+					fmt.Fprintf(stdout, "Found PascalCase import to rename: %s -> %s in %s\n", name, newName, displayPath(filePath))
 				}
 			}
+			if isBarrelFile(f.Name()) {
+				addDivergentBarrelFiles(filePath, string(content))
+			}
 		}
 	}
 
@@ -224,108 +408,619 @@ func buildRenameMap(dir string) error {
 			if err := buildRenameMap(subdir); err != nil {
 				return err
 			}
+			addBarrelFolder(subdir)
 		}
 	}
 
 	return nil
 }
 
-func updateFileContent(filePath string) error {
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		return err
+// addBarrelFolder adds a component that is only a folder with an index
+// barrel (ui/Dialog/index.ts and no Dialog.vue), which nothing may import by
+// name, so its folder is still renamed.
+func addBarrelFolder(dir string) {
+	name := filepath.Base(dir)
+	if !isComponentName(name) {
+		return
+	}
+	for _, barrel := range barrelFileNames {
+		if _, err := os.Stat(filepath.Join(dir, barrel)); err == nil {
+			if newName, ok := addDetectedRename(name); ok {
+				fmt.Fprintf(stdout, "Found component folder to rename: %s -> %s\n", name, newName)
+			}
+			return
+		}
+	}
+}
+
+var barrelDefaultExportRegex = regexp.MustCompile(`export\s*{\s*default\s+as\s+([A-Z][a-zA-Z0-9]+)\s*}\s*from\s*['"]\./([A-Z][a-zA-Z0-9]*)\.vue['"]`)
+
+// addDivergentBarrelFiles handles a barrel that exports a file under a
+// different name, e.g. export { default as AccordionItem } from './Item.vue'
+// in accordion/. The file is named by what is on disk, so Item.vue becomes
+// item.vue, while the exported identifier stays as it is.
+func addDivergentBarrelFiles(barrelPath, content string) {
+	dir := filepath.Dir(barrelPath)
+	for _, match := range barrelDefaultExportRegex.FindAllStringSubmatch(content, -1) {
+		exported, file := match[1], match[2]
+		if exported == file || !isPascalCase(exported) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, file+".vue")); err != nil {
+			continue
+		}
+		if newName, ok := addDetectedRename(file); ok {
+			fmt.Fprintf(stdout, "Found %s exported as %s to rename: %s -> %s in %s\n", file+".vue", exported, file, newName, displayPath(barrelPath))
+		}
+	}
+}
+
+// addDetectedRename adds a name found while scanning, unless it is already
+// mapped or is its own kebab-case form, which would only add no-op rewrites.
+func addDetectedRename(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	if _, exists := globalRenames[name]; exists {
+		return "", false
+	}
+	newName := toOutputCase(name)
+	if opts.stripUIPrefix && renameTargetTaken(newName) {
+		newName = convertName(name, false)
+		fmt.Fprintf(stdout, "Keeping the ui prefix of %s: %s is already taken\n", name, toOutputCase(name))
+	}
+	if newName == name || !addRename(name, newName) {
+		return "", false
+	}
+	return newName, true
+}
+
+func renameTargetTaken(newName string) bool {
+	for _, target := range globalRenames {
+		if target == newName {
+			return true
+		}
+	}
+	return false
+}
+
+func excludeComponents(names []string) {
+	for _, name := range names {
+		if _, exists := globalRenames[name]; exists {
+			delete(globalRenames, name)
+			fmt.Fprintf(stdout, "Excluding component from rename: %s\n", name)
+		}
+	}
+}
+
+// subcomponentSuffix names the part of a sub-component such as DialogContent
+// that follows its parent: "-content" normally, ".content" with --subsep .,
+// "content" with --no-subsplit.
+func subcomponentSuffix(suffix string) string {
+	if opts.noSubsplit {
+		return strings.ToLower(suffix)
+	}
+	separator := opts.subsep
+	if separator == "" {
+		separator = "-"
 	}
+	return separator + toKebabCase(suffix)
+}
 
-	newContent := string(content)
-	originalContent := newContent
+// joinSubcomponentNames renames sub-components that live in their parent's
+// folder (ui/Dialog/DialogContent.vue) as the parent's name followed by
+// subcomponentSuffix, so DialogContent becomes dialogcontent or
+// dialog.content rather than dialog-content.
+func joinSubcomponentNames(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".vue" {
+			return nil
+		}
 
-	for oldName, newName := range globalRenames {
+		parent := filepath.Base(filepath.Dir(path))
+		name := strings.TrimSuffix(d.Name(), ".vue")
+		parentName, ok := globalRenames[parent]
+		if !ok || name == parent || !strings.HasPrefix(name, parent) {
+			return nil
+		}
+		if _, ok := globalRenames[name]; ok {
+			globalRenames[name] = parentName + subcomponentSuffix(name[len(parent):])
+		}
+		return nil
+	})
+}
+
+// aliasPattern matches the srcDir (@/, ~/) and rootDir (@@/, ~~/) aliases
+// as well as a bare leading slash.
+const aliasPattern = `(?:@@|~~|@|~)?/`
+
+type rewritePattern struct {
+	old string
+	new string
+}
 
-		stringPatterns := []struct {
-			old string
-			new string
-		}{
+func stringPatterns(oldName, newName string) []rewritePattern {
+	return []rewritePattern{
 
-			{fmt.Sprintf("export { default as %s } from './%s.vue'", oldName, oldName), fmt.Sprintf("export { default as %s } from './%s.vue'", oldName, newName)},
+		{fmt.Sprintf("export { default as %s } from './%s.vue'", oldName, oldName), fmt.Sprintf("export { default as %s } from './%s.vue'", oldName, newName)},
 
-			{fmt.Sprintf("from '@/components/ui/%s.vue'", oldName), fmt.Sprintf("from '@/components/ui/%s.vue'", newName)},
-			{fmt.Sprintf("from '@/components/ui/%s'", oldName), fmt.Sprintf("from '@/components/ui/%s'", newName)},
-			{fmt.Sprintf("from '~/components/ui/%s.vue'", oldName), fmt.Sprintf("from '~/components/ui/%s.vue'", newName)},
-			{fmt.Sprintf("from '~/components/ui/%s'", oldName), fmt.Sprintf("from '~/components/ui/%s'", newName)},
+		{fmt.Sprintf("from '@/components/ui/%s.vue'", oldName), fmt.Sprintf("from '@/components/ui/%s.vue'", newName)},
+		{fmt.Sprintf("from '@/components/ui/%s'", oldName), fmt.Sprintf("from '@/components/ui/%s'", newName)},
+		{fmt.Sprintf("from '~/components/ui/%s.vue'", oldName), fmt.Sprintf("from '~/components/ui/%s.vue'", newName)},
+		{fmt.Sprintf("from '~/components/ui/%s'", oldName), fmt.Sprintf("from '~/components/ui/%s'", newName)},
 
-			{fmt.Sprintf("from './%s.vue'", oldName), fmt.Sprintf("from './%s.vue'", newName)},
-			{fmt.Sprintf("from './%s'", oldName), fmt.Sprintf("from './%s'", newName)},
-			{fmt.Sprintf("from '../%s.vue'", oldName), fmt.Sprintf("from '../%s.vue'", newName)},
-			{fmt.Sprintf("from '../%s'", oldName), fmt.Sprintf("from '../%s'", newName)},
-			{fmt.Sprintf("from '../../%s.vue'", oldName), fmt.Sprintf("from '../../%s.vue'", newName)},
-			{fmt.Sprintf("from '../../%s'", oldName), fmt.Sprintf("from '../../%s'", newName)},
+		{fmt.Sprintf("from './%s.vue'", oldName), fmt.Sprintf("from './%s.vue'", newName)},
+		{fmt.Sprintf("from './%s'", oldName), fmt.Sprintf("from './%s'", newName)},
+		{fmt.Sprintf("from '../%s.vue'", oldName), fmt.Sprintf("from '../%s.vue'", newName)},
+		{fmt.Sprintf("from '../%s'", oldName), fmt.Sprintf("from '../%s'", newName)},
+		{fmt.Sprintf("from '../../%s.vue'", oldName), fmt.Sprintf("from '../../%s.vue'", newName)},
+		{fmt.Sprintf("from '../../%s'", oldName), fmt.Sprintf("from '../../%s'", newName)},
 
-			{fmt.Sprintf("import %s from '@/components/ui/%s.vue'", oldName, oldName), fmt.Sprintf("import %s from '@/components/ui/%s.vue'", oldName, newName)},
-			{fmt.Sprintf("import %s from '~/components/ui/%s.vue'", oldName, oldName), fmt.Sprintf("import %s from '~/components/ui/%s.vue'", oldName, newName)},
-			{fmt.Sprintf("import %s from './%s.vue'", oldName, oldName), fmt.Sprintf("import %s from './%s.vue'", oldName, newName)},
-			{fmt.Sprintf("import { %s } from '@/components/ui/%s'", oldName, oldName), fmt.Sprintf("import { %s } from '@/components/ui/%s'", oldName, newName)},
+		{fmt.Sprintf("import %s from '@/components/ui/%s.vue'", oldName, oldName), fmt.Sprintf("import %s from '@/components/ui/%s.vue'", oldName, newName)},
+		{fmt.Sprintf("import %s from '~/components/ui/%s.vue'", oldName, oldName), fmt.Sprintf("import %s from '~/components/ui/%s.vue'", oldName, newName)},
+		{fmt.Sprintf("import %s from './%s.vue'", oldName, oldName), fmt.Sprintf("import %s from './%s.vue'", oldName, newName)},
+		{fmt.Sprintf("import { %s } from '@/components/ui/%s'", oldName, oldName), fmt.Sprintf("import { %s } from '@/components/ui/%s'", oldName, newName)},
 
-			{fmt.Sprintf("/%s/%s.vue'", oldName, oldName), fmt.Sprintf("/%s/%s.vue'", newName, newName)},
-			{fmt.Sprintf("/%s/%s'", oldName, oldName), fmt.Sprintf("/%s/%s'", newName, newName)},
+		{fmt.Sprintf("/%s/%s.vue'", oldName, oldName), fmt.Sprintf("/%s/%s.vue'", newName, newName)},
+		{fmt.Sprintf("/%s/%s'", oldName, oldName), fmt.Sprintf("/%s/%s'", newName, newName)},
+
+		{fmt.Sprintf("from '@/components/ui/%s/%s.vue'", oldName, oldName), fmt.Sprintf("from '@/components/ui/%s/%s.vue'", newName, newName)},
+		{fmt.Sprintf("from '@/components/ui/%s/%s'", oldName, oldName), fmt.Sprintf("from '@/components/ui/%s/%s'", newName, newName)},
+		{fmt.Sprintf("import %s from '@/components/ui/%s/%s.vue'", oldName, oldName, oldName), fmt.Sprintf("import %s from '@/components/ui/%s/%s.vue'", oldName, newName, newName)},
+		{fmt.Sprintf("import { %s } from '@/components/ui/%s/%s'", oldName, oldName, oldName), fmt.Sprintf("import { %s } from '@/components/ui/%s/%s'", oldName, newName, newName)},
+
+		{fmt.Sprintf("from '@/components/ui/%s/%s'", oldName, oldName+"Content"), fmt.Sprintf("from '@/components/ui/%s/%s'", newName, newName+subcomponentSuffix("Content"))},
+		{fmt.Sprintf("import { %sContent } from '@/components/ui/%s/%s'", oldName, oldName, oldName+"Content"), fmt.Sprintf("import { %sContent } from '@/components/ui/%s/%s'", oldName, newName, newName+subcomponentSuffix("Content"))},
+	}
+}
+
+// uiSubfolders matches the folders, if any, between components/ui/ and a
+// component's own segment.
+const uiSubfolders = `(?:[^/'"]+/)*`
+
+func regexPatterns(oldName, newName string) []rewritePattern {
+	return []rewritePattern{
+
+		// Any folders between ui/ and the component, such as a versioned
+		// ui/v2/, are kept as they are.
+		{
+			fmt.Sprintf(`([@~/]components/ui/%s)%s(/[^'"]+)`, uiSubfolders, oldName),
+			fmt.Sprintf(`${1}%s${2}`, newName),
+		},
+
+		{
+			fmt.Sprintf(`(['"]%scomponents/ui/)%s((?:\.vue)?['"])`, aliasPattern, oldName),
+			fmt.Sprintf(`${1}%s${2}`, newName),
+		},
+
+		{
+			fmt.Sprintf(`([@~/]components/ui/%s%s/)%s((?:\.vue)?['"/])`, uiSubfolders, oldName, oldName),
+			fmt.Sprintf(`${1}%s${2}`, newName),
+		},
+
+		{
+			fmt.Sprintf(`([@~/]components/ui/%s%s/)%sContent((?:\.vue)?['"/])`, uiSubfolders, oldName, oldName),
+			fmt.Sprintf(`${1}%s%s${2}`, newName, subcomponentSuffix("Content")),
+		},
+
+		{
+			fmt.Sprintf(`([@~/]components/ui/(?:[^/'"]+/)+)%s(%s?['"])`, oldName, leafSuffix()),
+			fmt.Sprintf(`${1}%s${2}`, newName),
+		},
+
+		// Relative folder segment, e.g. an aggregate barrel's
+		// export * from './Dialog/index'.
+		{
+			fmt.Sprintf(`(['"]\.\.?/(?:[^'"]*/)?)%s(/)`, oldName),
+			fmt.Sprintf(`${1}%s${2}`, newName),
+		},
+
+		// Relative leaf, including a Pascal file inside an already-kebab
+		// folder (./dialog/Dialog.vue) or a sibling renamed with the
+		// component (./Button.css with --story-glob '*.css'); only the file
+		// segment is rewritten.
+		{
+			fmt.Sprintf(`(['"]\.\.?/(?:[^'"]*/)?)%s(%s?['"])`, oldName, leafSuffix()),
+			fmt.Sprintf(`${1}%s${2}`, newName),
+		},
+	}
+}
+
+var (
+	regexCache   = make(map[string]*regexp.Regexp)
+	regexCacheMu sync.Mutex
+)
 
-			{fmt.Sprintf("from '@/components/ui/%s/%s.vue'", oldName, oldName), fmt.Sprintf("from '@/components/ui/%s/%s.vue'", newName, newName)},
-			{fmt.Sprintf("from '@/components/ui/%s/%s'", oldName, oldName), fmt.Sprintf("from '@/components/ui/%s/%s'", newName, newName)},
-			{fmt.Sprintf("import %s from '@/components/ui/%s/%s.vue'", oldName, oldName, oldName), fmt.Sprintf("import %s from '@/components/ui/%s/%s.vue'", oldName, newName, newName)},
-			{fmt.Sprintf("import { %s } from '@/components/ui/%s/%s'", oldName, oldName, oldName), fmt.Sprintf("import { %s } from '@/components/ui/%s/%s'", oldName, newName, newName)},
+func compilePattern(pattern string) *regexp.Regexp {
+	regexCacheMu.Lock()
+	defer regexCacheMu.Unlock()
 
-			{fmt.Sprintf("from '@/components/ui/%s/%s'", oldName, oldName+"Content"), fmt.Sprintf("from '@/components/ui/%s/%s'", newName, newName+"-content")},
-			{fmt.Sprintf("import { %sContent } from '@/components/ui/%s/%s'", oldName, oldName, oldName+"Content"), fmt.Sprintf("import { %sContent } from '@/components/ui/%s/%s'", oldName, newName, newName+"-content")},
+	re, ok := regexCache[pattern]
+	if !ok {
+		re = regexp.MustCompile(pattern)
+		regexCache[pattern] = re
+	}
+	return re
+}
+
+// importStatementRegex matches import/export statements up to and including
+// their module specifier, plus side-effect and dynamic imports. Rewrites are
+// confined to these spans so component paths in ordinary strings are kept.
+// A statement never spans a semicolon, so several imports on one line are
+// matched and rewritten independently. Ambient module declarations in .d.ts
+// files (declare module '@/components/ui/Button') are rewritten the same way.
+var importStatementRegex = regexp.MustCompile(
+	`\b(?:import|export)\b[^;'"` + "`" + `]*?\bfrom\s*(?:'[^'\n]*'|"[^"\n]*")` +
+		`|\bimport\s*\(?\s*(?:'[^'\n]*'|"[^"\n]*")` +
+		`|\bdeclare\s+module\s*(?:'[^'\n]*'|"[^"\n]*")`)
+
+func rewriteImports(filePath, content string) string {
+	return replaceOutsideComments(importStatementRegex, content, func(statement string) string {
+		rewritten := rewriteImportStatement(filePath, statement)
+		if rewritten != statement {
+			rewritten = applyQuoteStyle(rewritten, opts.quote)
+		}
+		return rewritten
+	})
+}
+
+func rewriteImportStatement(filePath, statement string) string {
+	closing := len(statement) - 1
+	opening := strings.LastIndexByte(statement[:closing], statement[closing])
+
+	// Stray whitespace just inside the quotes (' ./Button.vue ') is set aside
+	// while the specifier is rewritten and put back after.
+	specifier := statement[opening+1 : closing]
+	if trimmed := strings.TrimSpace(specifier); trimmed != specifier && trimmed != "" {
+		lead := specifier[:strings.Index(specifier, trimmed)]
+		trail := specifier[len(lead)+len(trimmed):]
+		rewritten := rewriteImportStatement(filePath, statement[:opening+1]+trimmed+statement[closing:])
+		closing = len(rewritten) - 1
+		opening = strings.LastIndexByte(rewritten[:closing], rewritten[closing])
+		return rewritten[:opening+1] + lead + rewritten[opening+1:closing] + trail + rewritten[closing:]
+	}
+
+	path, suffix := splitSpecifierSuffix(specifier)
+	if debugTarget(filePath) {
+		debugf(filePath, "%s: statement %q", displayPath(filePath), statement)
+	}
+	if outsideScope(filePath, path) {
+		if debugTarget(filePath) {
+			debugf(filePath, "  %s is outside --scope, kept as written", path)
+		}
+		return statement
+	}
+
+	if opts.dirsOnly {
+		resolved := rewriteFolderSegments(filePath, path)
+		if debugTarget(filePath) {
+			debugf(filePath, "  --dirs-only resolved %s -> %s", path, resolved)
 		}
+		if resolved != path {
+			logf(filePath, "Found path to update in %s: %s -> %s\n", displayPath(filePath), path, resolved)
+		}
+		return statement[:opening+1] + resolved + suffix + statement[closing:]
+	}
 
-		for _, pattern := range stringPatterns {
-			if strings.Contains(newContent, pattern.old) {
-				fmt.Printf("Found string pattern to update in %s: %s -> %s\n", filePath, pattern.old, pattern.new)
-				newContent = strings.ReplaceAll(newContent, pattern.old, pattern.new)
+	if componentIndex != nil {
+		resolved, ok := resolveAliasedSpecifier(path)
+		if isRelativeSpecifier(path) {
+			resolved, ok = resolveRelativeSpecifier(filePath, path), true
+		} else if !ok {
+			if resolved, ok = resolveComponentsSpecifier(path); !ok && opts.collapseToIndex {
+				resolved, ok = resolveCollapsedUISpecifier(path)
+			}
+		}
+		if ok {
+			if debugTarget(filePath) {
+				debugf(filePath, "  resolved through the component index: %s -> %s", path, resolved)
 			}
+			if resolved != path {
+				logf(filePath, "Found path to update in %s: %s -> %s\n", displayPath(filePath), path, resolved)
+			}
+			return statement[:opening+1] + resolved + suffix + statement[closing:]
+		}
+	}
+
+	// The patterns are written for single quotes and .vue files, so a
+	// double-quoted or compiled (.js) specifier is matched as if it were one
+	// and its quotes and extension are put back after.
+	path, ext := splitCompiledExtension(path)
+	var rewritten string
+	if statement[closing] == '"' && !strings.Contains(path, "'") {
+		rewritten = rewriteStatement(filePath, statement[:opening]+"'"+path+"'")
+		rewritten = applyQuoteStyle(rewritten, "double")
+	} else {
+		rewritten = rewriteStatement(filePath, statement[:opening+1]+path+statement[closing:])
+	}
+	if ext != "" {
+		rewritten = strings.TrimSuffix(rewritten[:len(rewritten)-1], ".vue") + ext + rewritten[len(rewritten)-1:]
+	}
+	return rewritten[:len(rewritten)-1] + suffix + rewritten[len(rewritten)-1:]
+}
+
+// leafSuffix matches what may follow a component's name in the last segment
+// of a specifier: .vue or one of the sibling suffixes renamed with it.
+func leafSuffix() string {
+	alternatives := []string{`\.vue`}
+	for _, suffix := range siblingSuffixes {
+		alternatives = append(alternatives, regexp.QuoteMeta(suffix))
+	}
+	return "(?:" + strings.Join(alternatives, "|") + ")"
+}
+
+func splitCompiledExtension(path string) (string, string) {
+	for _, ext := range componentExtensions {
+		if ext != ".vue" && strings.HasSuffix(path, ext) {
+			return strings.TrimSuffix(path, ext) + ".vue", ext
 		}
+	}
+	return path, ""
+}
 
-		regexPatterns := []struct {
-			old string
-			new string
-		}{
+// applyQuoteStyle requotes a statement's module specifier for --quote. A
+// specifier that contains the target quote character is left as it is.
+func applyQuoteStyle(statement, style string) string {
+	var quote byte
+	switch style {
+	case "single":
+		quote = '\''
+	case "double":
+		quote = '"'
+	default:
+		return statement
+	}
 
-			{
-				fmt.Sprintf(`([@~/]components/ui/)%s(/[^'"]+)`, oldName),
-				fmt.Sprintf(`${1}%s${2}`, newName),
-			},
+	closing := len(statement) - 1
+	opening := strings.LastIndexByte(statement[:closing], statement[closing])
+	specifier := statement[opening+1 : closing]
+	if strings.IndexByte(specifier, quote) >= 0 {
+		return statement
+	}
+	return statement[:opening] + string(quote) + specifier + string(quote)
+}
 
-			{
-				fmt.Sprintf(`(['"][@~/]components/ui/)%s(['"])`, oldName),
-				fmt.Sprintf(`${1}%s${2}`, newName),
-			},
+// splitSpecifierSuffix separates a Vite-style query (?raw) or fragment (#id)
+// from a module specifier. A leading # is a subpath import, not a fragment.
+func splitSpecifierSuffix(specifier string) (string, string) {
+	end := strings.IndexByte(specifier, '?')
+	if hash := strings.IndexByte(specifier, '#'); hash > 0 && (end < 0 || hash < end) {
+		end = hash
+	}
+	if end < 0 {
+		return specifier, ""
+	}
+	return specifier[:end], specifier[end:]
+}
 
-			{
-				fmt.Sprintf(`([@~/]components/ui/%s/)%s`, oldName, oldName),
-				fmt.Sprintf(`${1}%s`, newName),
-			},
+var fromGapRegex = regexp.MustCompile(`\bfrom(\s*)['"]`)
 
-			{
-				fmt.Sprintf(`([@~/]components/ui/%s/)%sContent`, oldName, oldName),
-				fmt.Sprintf(`${1}%s-content`, newName),
-			},
+func rewriteStatement(filePath, statement string) string {
+	// A from clause a formatter wrapped (} from\n'@/components/ui/Button') is
+	// matched in the one-space form the string patterns are written for, and
+	// its whitespace is put back after.
+	if gap := fromGapRegex.FindStringSubmatchIndex(statement); gap != nil && statement[gap[2]:gap[3]] != " " {
+		head := statement[:gap[2]]
+		rewritten := rewriteStatement(filePath, head+" "+statement[gap[3]:])
+		if rest, ok := strings.CutPrefix(rewritten, head+" "); ok {
+			return head + statement[gap[2]:gap[3]] + rest
 		}
+		return rewritten
+	}
 
-		for _, pattern := range regexPatterns {
-			re := regexp.MustCompile(pattern.old)
-			if re.MatchString(newContent) {
-				fmt.Printf("Found regex pattern to update in %s: %s -> %s\n", filePath, pattern.old, pattern.new)
-				newContent = re.ReplaceAllString(newContent, pattern.new)
+	debug := debugTarget(filePath)
+	for _, oldName := range longestRenameNames() {
+		newName := globalRenames[oldName]
+		if debug {
+			debugf(filePath, " %s -> %s", oldName, newName)
+		}
+		for _, pattern := range stringPatterns(oldName, newName) {
+			matched := strings.Contains(statement, pattern.old)
+			if tracing() {
+				tracef("pattern %s: string %q in %q matched=%v", filePath, pattern.old, statement, matched)
+			}
+			if debug {
+				debugPattern(filePath, "string", pattern.old, pattern.old, matched)
+			}
+			if matched {
+				logf(filePath, "Found string pattern to update in %s: %s -> %s\n", displayPath(filePath), pattern.old, pattern.new)
+				statement = strings.ReplaceAll(statement, pattern.old, pattern.new)
+			}
+		}
+
+		for _, pattern := range append(regexPatterns(oldName, newName), aliasPatterns(oldName, newName)...) {
+			re := compilePattern(pattern.old)
+			match := re.FindString(statement)
+			if tracing() {
+				tracef("pattern %s: regex %s in %q matched %q", filePath, pattern.old, statement, match)
+			}
+			if debug {
+				debugPattern(filePath, "regex", pattern.old, match, match != "")
+			}
+			if match != "" {
+				logf(filePath, "Found regex pattern to update in %s: %s -> %s\n", displayPath(filePath), pattern.old, pattern.new)
+				statement = re.ReplaceAllString(statement, pattern.new)
+			}
+		}
+
+		if opts.ignoreCaseInDetection {
+			folded := "(?i:" + oldName + ")"
+			for _, pattern := range append(regexPatterns(folded, newName), aliasPatterns(folded, newName)...) {
+				if rewritten := replaceFolded(compilePattern(pattern.old), statement, pattern.new); rewritten != statement {
+					logf(filePath, "Found mixed-case path to update in %s: %s -> %s\n", displayPath(filePath), pattern.old, pattern.new)
+					statement = rewritten
+				}
 			}
 		}
 	}
+	return statement
+}
+
+// utf8BOM is kept in front of the content through a rewrite; the rest of the
+// file is matched as if it were not there.
+const utf8BOM = "\uFEFF"
+
+// hasSkipMarker reports whether content carries the --skip-marker, e.g. a
+// "@generated" header; such files are never rewritten.
+func hasSkipMarker(content string) bool {
+	return opts.skipMarker != "" && strings.Contains(content, opts.skipMarker)
+}
+
+func rewriteContent(filePath, content string) string {
+	if hasSkipMarker(content) {
+		return content
+	}
+	if rest, ok := strings.CutPrefix(content, utf8BOM); ok {
+		return utf8BOM + rewriteContent(filePath, rest)
+	}
+	if isMarkdownFile(filePath) {
+		return rewriteMarkdownFences(filePath, content)
+	}
+	newContent := rewriteEscapedImports(filePath, rewriteImports(filePath, content))
+
+	if rewritten := rewriteTemplateLiterals(newContent); rewritten != newContent {
+		logf(filePath, "Found template literal path to update in %s\n", displayPath(filePath))
+		newContent = rewritten
+	}
+	if rewritten := rewriteCustomBlocks(filePath, newContent); rewritten != newContent {
+		logf(filePath, "Found custom block src to update in %s\n", displayPath(filePath))
+		newContent = rewritten
+	}
+	if rewritten := rewriteComponentTags(filePath, newContent); rewritten != newContent {
+		logf(filePath, "Found component tags to update in %s\n", displayPath(filePath))
+		newContent = rewritten
+	}
+	return newContent
+}
+
+func updateFileContent(filePath string) error {
+	filePath, ok := followSymlink(filePath)
+	if !ok {
+		return nil
+	}
+	content, err := readFileLimited(filePath)
+	tracef("read %s (err: %v)", filePath, err)
+	if err != nil {
+		return err
+	}
+
+	originalContent := string(content)
+	if hasSkipMarker(originalContent) {
+		logf(filePath, "Skipping file marked %s: %s\n", opts.skipMarker, displayPath(filePath))
+		return nil
+	}
+	newContent := rewriteContent(filePath, originalContent)
 
 	if newContent != originalContent {
-		fmt.Printf("Updated imports in: %s\n", filePath)
-		return os.WriteFile(filePath, []byte(newContent), 0644)
+		logf(filePath, "Updated imports in: %s\n", displayPath(filePath))
+		err := writeFileRetrying(filePath, []byte(newContent), 0644)
+		tracef("write %s (%d bytes, err: %v)", filePath, len(newContent), err)
+		if err != nil {
+			return err
+		}
+		recordChange(filePath)
+		if opts.emitCodemod != "" {
+			recordSpecifierChanges(filePath, originalContent, newContent)
+		}
+	}
+	return nil
+}
+
+var barrelFileNames = []string{"index.ts", "index.js"}
+
+func isBarrelFile(name string) bool {
+	for _, barrel := range barrelFileNames {
+		if strings.EqualFold(name, barrel) {
+			return true
+		}
+	}
+	return false
+}
+
+func renamePath(oldPath, newPath string) error {
+	if opts.keepBarrelNames && isBarrelFile(filepath.Base(oldPath)) {
+		fmt.Fprintf(stdout, "Keeping barrel file name: %s\n", displayPath(oldPath))
+		return nil
+	}
+
+	if opts.emitScript {
+		emitRename(oldPath, newPath)
+		return nil
 	}
+
+	if opts.useGitMv {
+		if err := gitMove(oldPath, newPath); err != nil {
+			fmt.Fprintf(stdout, "Warning: git mv failed for %s (%v), falling back to a plain rename\n", displayPath(oldPath), err)
+			if err := renameRetrying(oldPath, newPath); err != nil {
+				return err
+			}
+		}
+	} else if err := renameRetrying(oldPath, newPath); err != nil {
+		return err
+	}
+	tracef("rename %s -> %s", oldPath, newPath)
+	recordRename(oldPath, newPath)
+	fmt.Fprintf(stdout, "Renamed: %s -> %s\n", displayPath(oldPath), displayPath(newPath))
 	return nil
 }
 
+// componentExtensions are the file extensions a component can have on disk,
+// and so the files that are renamed. --ext adds compiled variants such as .js
+// for dist trees.
+var componentExtensions = []string{".vue"}
+
+// normalizeExtensions gives each of exts, as written on the command line with
+// or without its dot, a leading dot.
+func normalizeExtensions(exts []string) []string {
+	normalized := make([]string, 0, len(exts))
+	for _, ext := range exts {
+		normalized = append(normalized, "."+strings.TrimPrefix(ext, "."))
+	}
+	return normalized
+}
+
+var siblingSuffixes = []string{".stories.ts", ".stories.js", ".test.ts", ".spec.ts"}
+
+// isScriptSibling reports whether name is a .js sibling such as
+// Button.stories.js. It is renamed with its component, so its imports are
+// rewritten too, although .js files are not rewritten otherwise.
+func isScriptSibling(name string) bool {
+	for _, suffix := range siblingSuffixes {
+		if filepath.Ext(suffix) == ".js" && strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// storyGlobSuffixes turns --story-glob patterns such as *.story.vue into the
+// sibling suffixes they stand for. The * is the component's name, so it has
+// to come first and only once, followed by an extension.
+func storyGlobSuffixes(globs []string) ([]string, error) {
+	var suffixes []string
+	for _, glob := range globs {
+		suffix, ok := strings.CutPrefix(glob, "*")
+		if !ok || !strings.HasPrefix(suffix, ".") || len(suffix) < 2 || strings.ContainsAny(suffix, `*?[/\`) {
+			return nil, fmt.Errorf("--story-glob %q must be * followed by an extension, e.g. *.story.vue", glob)
+		}
+		suffixes = append(suffixes, suffix)
+	}
+	return suffixes, nil
+}
+
+func renameSiblings(dir, oldName, newName string) {
+	for _, suffix := range siblingSuffixes {
+		oldPath := filepath.Join(dir, oldName+suffix)
+		if _, err := os.Stat(oldPath); err != nil {
+			continue
+		}
+		if err := renamePath(oldPath, filepath.Join(dir, newName+suffix)); err != nil {
+			recordFailure(oldPath, err)
+		}
+	}
+}
+
 func processFiles(dir string) error {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -333,27 +1028,48 @@ func processFiles(dir string) error {
 	}
 
 	for _, f := range entries {
-		if !f.IsDir() {
+		if !f.IsDir() && !contentsRewritten {
 			filePath := filepath.Join(dir, f.Name())
-			ext := filepath.Ext(f.Name())
-			if ext == ".vue" || ext == ".ts" {
+			if isSourceFile(f.Name()) && inFileFilter(filePath) {
+				if activeCheckpoint.isDone(filePath) {
+					fmt.Fprintf(stdout, "Skipping already processed file: %s\n", displayPath(filePath))
+					continue
+				}
 				if err := updateFileContent(filePath); err != nil {
+					recordFailure(filePath, err)
+					continue
+				}
+				if err := activeCheckpoint.markDone(filePath); err != nil {
 					return err
 				}
 			}
 		}
 	}
 
-	for oldName, newName := range globalRenames {
-		oldPath := filepath.Join(dir, oldName+".vue")
-		if _, err := os.Stat(oldPath); err == nil {
-			newPath := filepath.Join(dir, newName+".vue")
-			if oldPath != newPath {
-				if err := os.Rename(oldPath, newPath); err != nil {
-					return err
-				}
-				fmt.Printf("Renamed: %s -> %s\n", oldPath, newPath)
+	for _, oldName := range sortedRenameNames() {
+		newName := globalRenames[oldName]
+		fileName := componentFileName(dir, oldName, newName)
+		if oldName == fileName || opts.dirsOnly {
+			continue
+		}
+		renamed := false
+		for _, ext := range componentExtensions {
+			oldPath := filepath.Join(dir, oldName+ext)
+			if _, err := os.Stat(oldPath); err != nil || !inFileFilter(oldPath) {
+				continue
+			}
+			rename := renamePath
+			if opts.followSymlinks && isSymlink(oldPath) {
+				rename = renameSymlinkedComponent
+			}
+			if err := rename(oldPath, filepath.Join(dir, fileName+ext)); err != nil {
+				recordFailure(oldPath, err)
+				continue
 			}
+			renamed = true
+		}
+		if renamed && oldName != newName {
+			renameSiblings(dir, oldName, newName)
 		}
 	}
 
@@ -363,67 +1079,638 @@ func processFiles(dir string) error {
 			if err := processFiles(subdir); err != nil {
 				return err
 			}
+
+			if newName, ok := globalRenames[entry.Name()]; ok && newName != entry.Name() && fileFilter == nil {
+				newDir := filepath.Join(dir, newName)
+				if err := renamePath(subdir, newDir); err != nil {
+					recordFailure(subdir, err)
+				}
+			}
 		}
 	}
 
 	return nil
 }
 
-func confirmChanges() bool {
-	reader := bufio.NewReader(os.Stdin)
-	fmt.Print("\nDo you want to proceed with these changes? (y/n): ")
-	response, err := reader.ReadString('\n')
-	if err != nil {
-		fmt.Printf("Error reading input: %v\n", err)
-		return false
+// updateSummary is the line under the proposal saying which files are
+// rewritten and to what case, as --rewrite-ext, --ext, --md and --case set it.
+func updateSummary() string {
+	exts := append([]string{}, rewriteExtensions...)
+	if opts.markdown {
+		exts = append(exts, ".md", ".mdx")
+	}
+	list := strings.Join(exts, ", ")
+	if i := strings.LastIndex(list, ", "); i >= 0 {
+		list = list[:i] + " and " + list[i+2:]
+	}
+	return fmt.Sprintf("This will update all imports in %s files to use the new %s names.", list, caseName())
+}
+
+// printProposal lists the rename map, cut to limit entries (0 for all) with a
+// count of the rest so the prompt stays in view on large runs. Components
+// sharing a prefix, such as Sidebar's many parts, are listed together under
+// a heading.
+func printProposal(w io.Writer, limit int) {
+	fmt.Fprintln(w, "\nProposed changes:")
+	fmt.Fprintln(w, "=================")
+	families := make(map[string][]string)
+	var prefixes []string
+	for _, name := range sortedRenameNames() {
+		prefix := familyPrefix(name)
+		if _, ok := families[prefix]; !ok {
+			prefixes = append(prefixes, prefix)
+		}
+		families[prefix] = append(families[prefix], name)
+	}
+	sort.Strings(prefixes)
+
+	shown, total := 0, len(globalRenames)
+	for _, prefix := range prefixes {
+		names := families[prefix]
+		indent := ""
+		if len(names) > 1 && (limit == 0 || shown < limit) {
+			fmt.Fprintf(w, "%s (%d):\n", prefix, len(names))
+			indent = "  "
+		}
+		for _, name := range names {
+			if limit > 0 && shown == limit {
+				break
+			}
+			fmt.Fprintf(w, "%s%s -> %s\n", indent, name, globalRenames[name])
+			shown++
+		}
+	}
+	if shown < total {
+		fmt.Fprintf(w, "...and %d more\n", total-shown)
 	}
+}
 
-	response = strings.ToLower(strings.TrimSpace(response))
-	return response == "y" || response == "yes"
+// familyPrefix is the longest of componentPrefixes that name starts with,
+// so AlertDialogContent is grouped with AlertDialog rather than Alert. A name
+// with no known prefix is its own family.
+func familyPrefix(name string) string {
+	family := name
+	longest := 0
+	for _, prefix := range componentPrefixes {
+		if strings.HasPrefix(name, prefix) && len(prefix) > longest {
+			family, longest = prefix, len(prefix)
+		}
+	}
+	return family
 }
 
 func main() {
 	var dir string
-	var err error
+	var scanTime, applyTime time.Duration
+	started := time.Now()
 
-	if len(os.Args) > 1 {
-		dir = os.Args[1]
+	if len(os.Args) > 1 && os.Args[1] == "apply" {
+		os.Exit(runApply(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "inventory" {
+		os.Exit(runInventory(os.Args[2:]))
+	}
+
+	parsed, args, err := parseArgs(os.Args[1:], os.Stderr)
+	if err != nil {
+		if err == flag.ErrHelp {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	}
+	opts = parsed
+	componentPrefixes = append(componentPrefixes, opts.prefixes...)
+	if opts.importsOnly != "" {
+		opts.emitScript = true
+	}
+	if opts.traceFile != "" {
+		if err := openTrace(opts.traceFile); err != nil {
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			exit(1)
+		}
+		tracef("args %q", os.Args[1:])
+	}
+	if opts.noDefaultAcronyms {
+		acronyms = nil
+	}
+	acronyms = append(acronyms, opts.acronyms...)
+	if len(opts.rewriteExts) > 0 {
+		rewriteExtensions = normalizeExtensions(opts.rewriteExts)
+	}
+	if len(opts.renameExts) > 0 {
+		componentExtensions = normalizeExtensions(opts.renameExts)
+	}
+	for _, ext := range normalizeExtensions(opts.exts) {
+		componentExtensions = append(componentExtensions, ext)
+		rewriteExtensions = append(rewriteExtensions, ext)
+	}
+	if len(opts.storyGlobs) > 0 {
+		suffixes, err := storyGlobSuffixes(opts.storyGlobs)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			exit(1)
+		}
+		siblingSuffixes = suffixes
+	}
+	if opts.sfcBlocks {
+		siblingSuffixes = append(siblingSuffixes, customBlockSuffixes...)
+	}
+	if opts.dumpAST != "" {
+		if err := dumpTokens(stdout, opts.dumpAST); err != nil {
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			exit(1)
+		}
+		exit(0)
+	}
+	if opts.silentIfClean || opts.jsonOutput {
+		holdOutput()
+	}
+
+	config, configPath, err := discoverComponentsJSON(opts.componentsJSON)
+	if err != nil {
+		fmt.Fprintf(stdout, "Error: %v\n", err)
+		exit(1)
+	}
+	if config != nil {
+		registerUIAlias(config.uiAlias())
+		if uiDir, err := config.uiDir(filepath.Dir(configPath)); err == nil {
+			configUIDir, _ = filepath.Abs(uiDir)
+		}
+	}
+
+	if len(args) > 0 {
+		dir = args[0]
+		if err := validateComponentsDir(dir); err != nil {
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			exit(1)
+		}
+	} else if config != nil {
+		dir, err = config.uiDir(filepath.Dir(configPath))
+		if err != nil {
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			exit(1)
+		}
+		fmt.Fprintf(stdout, "Found components directory from %s: %s\n", configPath, dir)
 	} else {
 		dir, err = findComponentsDir()
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			fmt.Println("Usage: rename_shadcn [components_directory]")
-			os.Exit(1)
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			fmt.Fprintln(stdout, "Usage: rename-shadcn-vue [flags] [components_directory]")
+			exit(1)
+		}
+	}
+
+	setPathBase(dir)
+	defer cleanup()
+	if opts.aliasTable != "" {
+		aliasMappings, err = loadAliasTable(opts.aliasTable)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			exit(1)
+		}
+		for _, mapping := range aliasMappings {
+			registerUIAlias(mapping.alias)
+		}
+	}
+
+	if opts.scope != "" && opts.outputDir != "" {
+		fmt.Fprintln(stdout, "Error: --scope cannot be combined with --output-dir")
+		exit(1)
+	}
+	if opts.since != "" && opts.outputDir != "" {
+		fmt.Fprintln(stdout, "Error: --since cannot be combined with --output-dir")
+		exit(1)
+	}
+	switch opts.quote {
+	case "single", "double", "preserve":
+	default:
+		fmt.Fprintf(stdout, "Error: --quote must be single, double or preserve, got %q\n", opts.quote)
+		exit(1)
+	}
+	if opts.confirmDefault != "y" && opts.confirmDefault != "n" {
+		fmt.Fprintf(stdout, "Error: --confirm-default must be y or n, got %q\n", opts.confirmDefault)
+		exit(1)
+	}
+	if opts.retries < 0 {
+		fmt.Fprintf(stdout, "Error: --retries must not be negative, got %d\n", opts.retries)
+		exit(1)
+	}
+	if opts.parallel < 1 {
+		fmt.Fprintf(stdout, "Error: --parallel must be at least 1, got %d\n", opts.parallel)
+		exit(1)
+	}
+	if opts.ioConcurrency < 1 {
+		fmt.Fprintf(stdout, "Error: --io-concurrency must be at least 1, got %d\n", opts.ioConcurrency)
+		exit(1)
+	}
+	ioSlots = make(chan struct{}, opts.ioConcurrency)
+	if !isNameCase(opts.fromCase) {
+		fmt.Fprintf(stdout, "Error: --from-case must be pascal, kebab or snake, got %q\n", opts.fromCase)
+		exit(1)
+	}
+	if !isOutputCase(opts.outputCase) {
+		fmt.Fprintf(stdout, "Error: --case must be pascal, kebab, snake or flat, got %q\n", opts.outputCase)
+		exit(1)
+	}
+	if opts.subsep == "" || strings.ContainsAny(opts.subsep, `/\'"`) {
+		fmt.Fprintf(stdout, "Error: --subsep must be a non-empty string without slashes or quotes, got %q\n", opts.subsep)
+		exit(1)
+	}
+
+	if opts.checkBarrel && (opts.emitScript || opts.dryRun) {
+		fmt.Fprintln(stdout, "Error: --check-barrel checks the tree after the renames, so it cannot be combined with --dry-run, --emit-script or --imports-only")
+		exit(1)
+	}
+	if opts.verify && !opts.dryRun {
+		fmt.Fprintln(stdout, "Error: --verify requires --dry-run")
+		exit(1)
+	}
+	if len(opts.jsonPaths) > 0 && opts.outputDir != "" {
+		fmt.Fprintln(stdout, "Error: --json-paths cannot be combined with --output-dir")
+		exit(1)
+	}
+
+	if opts.since != "" {
+		fileFilter, err = changedSince(opts.since, dir)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			exit(1)
 		}
+		fmt.Fprintf(stdout, "Limiting changes to %d file(s) changed since %s\n", len(fileFilter), opts.since)
 	}
 
-	if err := buildRenameMap(dir); err != nil {
-		fmt.Printf("Error building rename map: %v\n", err)
-		os.Exit(1)
+	scanStarted := time.Now()
+	if opts.entry != "" {
+		err = buildEntryRenameMap(dir, opts.entry)
+	} else {
+		err = buildRenameMap(dir)
+	}
+	if err != nil {
+		fmt.Fprintf(stdout, "Error building rename map: %v\n", err)
+		exit(1)
+	}
+	if opts.followReexports {
+		if err := followReexports(dir); err != nil {
+			fmt.Fprintf(stdout, "Error building rename map: %v\n", err)
+			exit(1)
+		}
+	}
+	scanTime = time.Since(scanStarted)
+	printUnrecognizedNames(stdout)
+
+	if opts.resume {
+		activeCheckpoint, err = loadCheckpoint(dir)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error reading checkpoint: %v\n", err)
+			exit(1)
+		}
+		for oldName, newName := range activeCheckpoint.renames {
+			addRename(oldName, newName)
+		}
+		if len(activeCheckpoint.done) > 0 {
+			fmt.Fprintf(stdout, "Resuming: %d file(s) already processed\n", len(activeCheckpoint.done))
+		}
+	} else {
+		activeCheckpoint = &checkpoint{dir: dir, done: make(map[string]bool)}
+	}
+
+	if opts.noSubsplit || opts.subsep != "-" {
+		if err := joinSubcomponentNames(dir); err != nil {
+			fmt.Fprintf(stdout, "Error building rename map: %v\n", err)
+			exit(1)
+		}
+	}
+	if opts.mapFile != "" {
+		renames, err := loadRenameMap(opts.mapFile)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			exit(1)
+		}
+		for oldName, newName := range renames {
+			addRename(oldName, newName)
+		}
+		fmt.Fprintf(stdout, "Loaded %d rename(s) from %s\n", len(renames), opts.mapFile)
+	}
+	if opts.allowFile != "" {
+		allowed, err := loadAllowList(opts.allowFile)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			exit(1)
+		}
+		restrictToAllowList(allowed)
+	}
+	excludeComponents(opts.excludes)
+
+	if opts.renameMapOut != "" {
+		if err := writeRenameMap(opts.renameMapOut, globalRenames); err != nil {
+			fmt.Fprintf(stdout, "Error writing rename map: %v\n", err)
+			exit(1)
+		}
+		fmt.Fprintf(stdout, "Wrote rename map to %s\n", opts.renameMapOut)
 	}
 
 	if len(globalRenames) == 0 {
-		fmt.Println("No PascalCase imports found to rename.")
+		fmt.Fprintln(stdout, "No PascalCase imports found to rename.")
+		exitClean(dir, scanTime, started)
+	}
+
+	if err := indexComponents(dir); err != nil {
+		fmt.Fprintf(stdout, "Error indexing components: %v\n", err)
+		exit(1)
+	}
+	if opts.scope != "" {
+		if info, err := os.Stat(opts.scope); err != nil || !info.IsDir() {
+			fmt.Fprintf(stdout, "Error: --scope %s is not a directory\n", opts.scope)
+			exit(1)
+		}
+		scope, err := filepath.Abs(opts.scope)
+		if err != nil || !isWithin(scope, componentRoot) {
+			fmt.Fprintf(stdout, "Error: --scope %s is not inside the components directory %s\n", opts.scope, dir)
+			exit(1)
+		}
+		scopeRoot = scope
+		fmt.Fprintf(stdout, "Limiting changes to %s; the rename map was built from %s\n", opts.scope, dir)
+		dir = opts.scope
+	}
+
+	if opts.silentIfClean {
+		affected, err := affectedFiles(dir)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error analyzing changes: %v\n", err)
+			exit(1)
+		}
+		if len(affected) == 0 {
+			exitClean(dir, scanTime, started)
+		}
+		if !opts.jsonOutput {
+			releaseOutput()
+		}
+	}
+
+	printProposal(stdout, opts.previewLimit)
+	fmt.Fprintln(stdout, "\n"+updateSummary())
+
+	if opts.usage {
+		if err := printUsageReport(stdout, dir); err != nil {
+			fmt.Fprintf(stdout, "Error reading files: %v\n", err)
+			exit(1)
+		}
+	}
+
+	if opts.tree {
+		fmt.Fprintln(stdout, "\nDirectory after renaming:")
+		if err := printTree(stdout, dir); err != nil {
+			fmt.Fprintf(stdout, "Error reading directory: %v\n", err)
+			exit(1)
+		}
+	}
+
+	if opts.maxChanges > 0 {
+		affected, err := affectedFiles(dir)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error analyzing changes: %v\n", err)
+			exit(1)
+		}
+		fmt.Fprintf(stdout, "%d file(s) will be changed.\n", len(affected))
+		if err := checkChangeLimit(len(affected), opts.maxChanges, opts.force); err != nil {
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			exit(1)
+		}
+	}
+
+	if opts.dryRun || opts.check || opts.patchFile != "" {
+		if err := printDryRun(dir); err != nil {
+			fmt.Fprintf(stdout, "Error analyzing changes: %v\n", err)
+			exit(1)
+		}
+		p, err := buildPlan(dir)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error building plan: %v\n", err)
+			exit(1)
+		}
+		r := buildReport(dir, scanTime, 0, time.Since(started))
+		r.Plan = p
+		for _, edit := range p.Edits {
+			r.FilesRewritten = append(r.FilesRewritten, displayPath(filepath.Join(dir, edit.Path)))
+		}
+		for _, rename := range p.Renames {
+			r.PathsRenamed = append(r.PathsRenamed, pathRename{
+				From: displayPath(filepath.Join(dir, rename.From)),
+				To:   displayPath(filepath.Join(dir, rename.To)),
+			})
+		}
+		r.Counts.FilesRewritten = len(r.FilesRewritten)
+		r.Counts.PathsRenamed = len(r.PathsRenamed)
+		if opts.reportFile != "" {
+			if err := writeReport(opts.reportFile, r); err != nil {
+				fmt.Fprintf(stdout, "Error writing report: %v\n", err)
+				exit(1)
+			}
+			fmt.Fprintf(stdout, "Wrote plan to %s; apply it with: rename-shadcn-vue apply --plan %s\n", opts.reportFile, opts.reportFile)
+		}
+		if opts.patchFile != "" {
+			if err := writePatch(opts.patchFile, dir); err != nil {
+				fmt.Fprintf(stdout, "Error writing patch: %v\n", err)
+				exit(1)
+			}
+			fmt.Fprintf(stdout, "Wrote patch to %s; apply it with: git apply %s\n", opts.patchFile, opts.patchFile)
+		}
+		if opts.verify {
+			unresolved, err := verifyPlan(dir)
+			if err != nil {
+				fmt.Fprintf(stdout, "Error verifying imports: %v\n", err)
+				exit(1)
+			}
+			if len(unresolved) > 0 {
+				printUnresolved(stdout, unresolved)
+				exit(1)
+			}
+			fmt.Fprintln(stdout, "\nVerified: every rewritten import resolves after the rename.")
+		}
+		pending := len(p.Edits)+len(p.Renames) > 0
+		if opts.check && pending {
+			fmt.Fprintln(stdout, "\nChanges are needed; run without --check to apply them.")
+		}
+		if opts.jsonOutput {
+			r.Plan = nil
+			printJSONReport(r)
+		}
+		cleanup()
+		if opts.check && pending {
+			os.Exit(1)
+		}
 		os.Exit(0)
 	}
 
-	fmt.Println("\nProposed changes:")
-	fmt.Println("=================")
-	for old, new := range globalRenames {
-		fmt.Printf("%s -> %s\n", old, new)
+	// --since leaves unchanged files alone, so a changed file's import of an
+	// unchanged component, or the reverse, can be left pointing at the old
+	// name. Such a run is refused rather than written half-renamed.
+	if fileFilter != nil {
+		unresolved, err := verifyPlan(dir)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error verifying imports: %v\n", err)
+			exit(1)
+		}
+		if len(unresolved) > 0 {
+			printUnresolved(stdout, unresolved)
+			fmt.Fprintf(stdout, "Files unchanged since %s are not renamed or rewritten; run without --since, or with --check or --dry-run.\n", opts.since)
+			exit(1)
+		}
 	}
-	fmt.Println("\nThis will update all imports in .vue and .ts files to use the new kebab-case names.")
 
-	if !confirmChanges() {
-		fmt.Println("Operation cancelled.")
+	// Only a run that may write takes the lock, so the read-only modes above
+	// neither write into the tree nor fail on a read-only checkout.
+	// The lock goes on the components root rather than a --scope folder, so
+	// two runs with different scopes still exclude each other.
+	releaseLockOnSignal()
+	if err := acquireLock(componentRoot, opts.forceLock); err != nil {
+		fmt.Fprintf(stdout, "Error: %v\n", err)
+		exit(1)
+	}
+
+	confirmed := opts.yes
+	if !confirmed && opts.assumeYesOnClean {
+		confirmed, err = onlyRenames(dir)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error analyzing changes: %v\n", err)
+			exit(1)
+		}
+		if confirmed {
+			fmt.Fprintln(stdout, "\nOnly file renames, no content edits; proceeding without confirmation.")
+		}
+	}
+
+	if !confirmed && !confirmChanges() {
+		fmt.Fprintln(promptOutput(), "Operation cancelled.")
+		cleanup()
 		os.Exit(0)
 	}
 
-	fmt.Println("\nProceeding with changes...")
+	if opts.outputDir != "" {
+		if err := prepareOutputDir(dir, opts.outputDir); err != nil {
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			exit(1)
+		}
+		if err := copyTree(dir, opts.outputDir); err != nil {
+			fmt.Fprintf(stdout, "Error copying %s to %s: %v\n", dir, opts.outputDir, err)
+			exit(1)
+		}
+		fmt.Fprintf(stdout, "\nWriting results to %s; %s is left untouched.\n", opts.outputDir, dir)
+		if err := rebaseAliasDirs(dir, opts.outputDir); err != nil {
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			exit(1)
+		}
+		dir = opts.outputDir
+		activeCheckpoint.dir = dir
+		setPathBase(dir)
+		if err := indexComponents(dir); err != nil {
+			fmt.Fprintf(stdout, "Error indexing components: %v\n", err)
+			exit(1)
+		}
+	}
+
+	fmt.Fprintln(stdout, "\nProceeding with changes...")
+	if err := activeCheckpoint.start(globalRenames); err != nil {
+		fmt.Fprintf(stdout, "Error writing checkpoint: %v\n", err)
+		exit(1)
+	}
+	applyStarted := time.Now()
+	if opts.parallel > 1 {
+		if err := updateContentsParallel(dir, opts.parallel); err != nil {
+			fmt.Fprintf(stdout, "Error processing files: %v\n", err)
+			fmt.Fprintf(stdout, "Progress was saved; re-run with --resume to continue.\n")
+			exit(1)
+		}
+	}
 	if err := processFiles(dir); err != nil {
-		fmt.Printf("Error processing files: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(stdout, "Error processing files: %v\n", err)
+		fmt.Fprintf(stdout, "Progress was saved; re-run with --resume to continue.\n")
+		exit(1)
+	}
+	if len(processFailures) > 0 {
+		printFailureSummary(stdout)
+		exit(1)
+	}
+	if err := rewriteJSONPaths(opts.jsonPaths); err != nil {
+		fmt.Fprintf(stdout, "Error: %v\n", err)
+		exit(1)
+	}
+	applyTime = time.Since(applyStarted)
+	if err := activeCheckpoint.finish(); err != nil {
+		fmt.Fprintf(stdout, "Error removing checkpoint: %v\n", err)
+	}
+
+	if opts.importsOnly != "" {
+		if err := writeRenameScriptFile(opts.importsOnly, renameScript); err != nil {
+			fmt.Fprintf(stdout, "Error writing rename script: %v\n", err)
+			exit(1)
+		}
+		fmt.Fprintf(stdout, "\nImports were updated in place. Commit them, then run %s to apply the %d rename(s) with git mv.\n", opts.importsOnly, len(renameScript))
+	} else if opts.emitScript {
+		fmt.Fprintf(stdout, "\nImports were updated in place. Apply the file renames with git:\n\n")
+		writeRenameScript(stdout, renameScript)
+	}
+
+	if opts.formatCmd != "" {
+		if err := runFormatter(opts.formatCmd, changedFileList()); err != nil {
+			fmt.Fprintf(stdout, "Error running formatter: %v\n", err)
+			exit(1)
+		}
+	}
+
+	if opts.emitCodemod != "" {
+		if err := writeCodemod(opts.emitCodemod, buildCodemod()); err != nil {
+			fmt.Fprintf(stdout, "Error writing codemod description: %v\n", err)
+			exit(1)
+		}
+		fmt.Fprintf(stdout, "Wrote codemod description to %s\n", opts.emitCodemod)
+	}
+	if opts.reportFile != "" {
+		if err := writeReport(opts.reportFile, buildReport(dir, scanTime, applyTime, time.Since(started))); err != nil {
+			fmt.Fprintf(stdout, "Error writing report: %v\n", err)
+			exit(1)
+		}
+		fmt.Fprintf(stdout, "Wrote report to %s\n", opts.reportFile)
+	}
+	if opts.checkBarrel {
+		mismatches, err := checkBarrels(dir)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error checking barrels: %v\n", err)
+			exit(1)
+		}
+		if len(mismatches) > 0 {
+			printBarrelMismatches(stdout, mismatches)
+			exit(1)
+		}
+		fmt.Fprintln(stdout, "Barrel check: every re-export points at a file on disk")
+	}
+	if opts.jsonOutput {
+		printJSONReport(buildReport(dir, scanTime, applyTime, time.Since(started)))
+		return
+	}
+
+	fmt.Fprintln(stdout, "\nAll changes completed successfully!")
+}
+
+func exitClean(dir string, scanTime time.Duration, started time.Time) {
+	discardOutput()
+	r := buildReport(dir, scanTime, 0, time.Since(started))
+	if opts.reportFile != "" {
+		if err := writeReport(opts.reportFile, r); err != nil {
+			fmt.Fprintf(stdout, "Error writing report: %v\n", err)
+			exit(1)
+		}
 	}
+	if opts.jsonOutput {
+		printJSONReport(r)
+	}
+	cleanup()
+	os.Exit(0)
+}
 
-	fmt.Println("\nAll changes completed successfully!")
+func setPathBase(dir string) {
+	pathBase = dir
+	if opts.relativeTo != "" {
+		pathBase = opts.relativeTo
+	}
 }
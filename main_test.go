@@ -1,11 +1,41 @@
 package main
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
+// writeTree writes files, keyed by slash-separated path, into a fresh
+// temporary directory and returns it.
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeFiles(t, dir, files)
+	return dir
+}
+
+// writeFiles writes files, keyed by slash-separated path, under dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		fullPath := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", path, err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file %s: %v", path, err)
+		}
+	}
+}
+
 func TestToKebabCase(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -20,6 +50,10 @@ func TestToKebabCase(t *testing.T) {
 		{"multiple uppercase", "HTMLInput", "html-input"},
 		{"already kebab", "button-group", "button-group"},
 		{"complex name", "DialogContentPanel", "dialog-content-panel"},
+		{"single letter", "A", "a"},
+		{"single lowercase letter", "x", "x"},
+		{"single letter suffix", "ButtonX", "button-x"},
+		{"single letter prefix", "XButton", "x-button"},
 	}
 
 	for _, tc := range tests {
@@ -39,6 +73,8 @@ func TestIsPascalCase(t *testing.T) {
 		expected bool
 	}{
 		{"empty string", "", false},
+		{"whitespace only", "   ", false},
+		{"tabs and newlines", "\t\r\n", false},
 		{"component name", "Button", true},
 		{"component with suffix", "ButtonGroup", true},
 		{"props type", "ButtonProps", false},
@@ -53,6 +89,8 @@ func TestIsPascalCase(t *testing.T) {
 		{"non-component pascal", "MyClass", false},
 		{"kebab case", "button-group", false},
 		{"camel case", "buttonGroup", false},
+		{"single letter", "A", false},
+		{"single letter X", "X", false},
 	}
 
 	for _, tc := range tests {
@@ -65,6 +103,112 @@ func TestIsPascalCase(t *testing.T) {
 	}
 }
 
+// A component named X is never renamed, even under --prefix X: isPascalCase
+// rejects single letters and the detection patterns need two characters.
+func TestSingleLetterComponents(t *testing.T) {
+	original := componentPrefixes
+	defer func() { componentPrefixes = original }()
+	componentPrefixes = append(componentPrefixes, "X")
+
+	content := `import X from './X.vue'
+import { A } from '@/components/ui/A'`
+	if result := findPascalCaseImports(content); len(result) != 0 {
+		t.Errorf("findPascalCaseImports() = %v; want none", result)
+	}
+	if isPascalCase("X") {
+		t.Errorf("isPascalCase(%q) = true under --prefix X; want false", "X")
+	}
+	if !isPascalCase("XWidget") {
+		t.Errorf("isPascalCase(%q) = false under --prefix X; want true", "XWidget")
+	}
+}
+
+func TestToKebabCaseAcronyms(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"OAuthButton", "oauth-button"},
+		{"ButtonOAuth", "button-oauth"},
+		{"TwoFAInput", "two-fa-input"},
+		{"Input2FA", "input2-fa"},
+		{"UIButton", "ui-button"},
+		{"SSOLoginButton", "sso-login-button"},
+		{"GUIDValue", "guid-value"},
+		{"BUILDInfo", "build-info"},
+		{"MyUIKit", "my-ui-kit"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			if result := toKebabCase(tc.input); result != tc.expected {
+				t.Errorf("toKebabCase(%q) = %q; want %q", tc.input, result, tc.expected)
+			}
+		})
+	}
+
+	original := acronyms
+	defer func() { acronyms = original }()
+
+	if result := toKebabCase("TOTPInput"); result != "totp-input" {
+		t.Errorf("toKebabCase(%q) = %q; want %q", "TOTPInput", result, "totp-input")
+	}
+	if result := toKebabCase("MyKeyInput"); result != "my-key-input" {
+		t.Fatalf("toKebabCase(%q) = %q; want %q", "MyKeyInput", result, "my-key-input")
+	}
+	acronyms = append(acronyms, "MyKey")
+	if result := toKebabCase("MyKeyInput"); result != "mykey-input" {
+		t.Errorf("toKebabCase(%q) with acronym MyKey = %q; want %q", "MyKeyInput", result, "mykey-input")
+	}
+
+	acronyms = nil
+	if result := toKebabCase("OAuthButton"); result != "o-auth-button" {
+		t.Errorf("toKebabCase(%q) without acronyms = %q; want %q", "OAuthButton", result, "o-auth-button")
+	}
+}
+
+func TestOverlappingPrefixFamilies(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Alert", "alert"},
+		{"AlertTitle", "alert-title"},
+		{"AlertDescription", "alert-description"},
+		{"AlertDialog", "alert-dialog"},
+		{"AlertDialogContent", "alert-dialog-content"},
+		{"AlertDialogTrigger", "alert-dialog-trigger"},
+		{"AlertDialogAction", "alert-dialog-action"},
+		{"AspectRatio", "aspect-ratio"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			if !isPascalCase(tc.input) {
+				t.Errorf("isPascalCase(%q) = false; want true", tc.input)
+			}
+			if result := toKebabCase(tc.input); result != tc.expected {
+				t.Errorf("toKebabCase(%q) = %q; want %q", tc.input, result, tc.expected)
+			}
+		})
+	}
+
+	globalRenames = map[string]string{
+		"Alert":              "alert",
+		"AlertDialog":        "alert-dialog",
+		"AlertDialogContent": "alert-dialog-content",
+	}
+	input := `import { Alert } from '@/components/ui/Alert'
+import { AlertDialog } from '@/components/ui/AlertDialog'
+import AlertDialogContent from '@/components/ui/AlertDialog/AlertDialogContent.vue'`
+	expected := `import { Alert } from '@/components/ui/alert'
+import { AlertDialog } from '@/components/ui/alert-dialog'
+import AlertDialogContent from '@/components/ui/alert-dialog/alert-dialog-content.vue'`
+	if result := rewriteImports("test.vue", input); result != expected {
+		t.Errorf("\nExpected:\n%s\n\nGot:\n%s", expected, result)
+	}
+}
+
 func TestFindPascalCaseImports(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -89,6 +233,51 @@ import { Button } from '@/components/ui/Button'`,
 import { Button as CustomButton } from './Button'`,
 			expected: []string{"Button"},
 		},
+		{
+			name: "whitespace inside the quotes",
+			content: `import Button from ' ./Button.vue '
+import { Card } from '@/components/ui/Card '`,
+			expected: []string{"Button", "Card"},
+		},
+		{
+			name: "imports without bindings",
+			content: `import {} from '@/components/ui/Button'
+import '@/components/ui/Card.vue'
+import './styles.css'`,
+			expected: []string{"Button", "Card"},
+		},
+		{
+			name:     "specifier match stays inside its quotes",
+			content:  `import a from './a';const url = '/Badge'`,
+			expected: []string{},
+		},
+		{
+			name:     "CRLF multiline imports",
+			content:  "import {\r\n  Button,\r\n  ButtonGroup\r\n} from '@/components/ui/Button'\r\nimport {\r\n  Card\r\n} from './Card.vue'\r\n",
+			expected: []string{"Button", "ButtonGroup", "Card"},
+		},
+		{
+			name: "type-only imports",
+			content: `import type { ButtonProps } from '@/components/ui/Button'
+export type { DialogContentProps } from './DialogContent.vue'`,
+			expected: []string{"DialogContent", "Button"},
+		},
+		{
+			name:     "from clause wrapped onto the next line",
+			content:  "import {\n  Button,\n} from\n'@/components/ui/Button'\nimport Dialog from\n  './Dialog.vue'",
+			expected: []string{"Dialog", "Button"},
+		},
+		{
+			name:     "versioned ui folder",
+			content:  "import { Button } from '@/components/ui/v2/Button'\nimport DialogContent from '@/components/ui/v2/Dialog/DialogContent.vue'",
+			expected: []string{"DialogContent", "Button"},
+		},
+		{
+			name: "escaped quotes in stringified code",
+			content: `const source = 'import Button from \'./Button.vue\''
+const json = "import { Dialog } from \"@/components/ui/Dialog\""`,
+			expected: []string{"Button", "Dialog"},
+		},
 		{
 			name: "multiline imports",
 			content: `import {
@@ -97,6 +286,21 @@ import { Button as CustomButton } from './Button'`,
 } from '@/components/ui/Button'`,
 			expected: []string{"Button", "ButtonGroup"},
 		},
+		{
+			name:     "namespace import",
+			content:  `import * as Ui from '@/components/ui/Dialog'`,
+			expected: []string{"Dialog"},
+		},
+		{
+			name:     "query suffix",
+			content:  `import buttonSource from '@/components/ui/Button.vue?raw'`,
+			expected: []string{"Button"},
+		},
+		{
+			name:     "template literal path",
+			content:  "const loader = () => import(`@/components/ui/${name}/Button.vue`)",
+			expected: []string{"Button"},
+		},
 		{
 			name: "commented imports",
 			content: `// import Button from './Button.vue'
@@ -122,6 +326,65 @@ import { Button as CustomButton } from './Button'`,
 	}
 }
 
+func TestFindPascalCaseImportsLongLine(t *testing.T) {
+	// A minified bundle: one line of a megabyte or so, with an unterminated
+	// specifier at the end for good measure.
+	bundle := func(n int) string {
+		return strings.Repeat(`import{ButtonGroup,CardTitle}from"./x/y";var a="/Badge",b=' from '+"`, n) + `from '/Dialog`
+	}
+	line := bundle(10000)
+	result := findPascalCaseImports(line)
+	expected := []string{"ButtonGroup", "CardTitle"}
+	if !slices.Equal(result, expected) {
+		t.Errorf("findPascalCaseImports() = %v; want %v", result, expected)
+	}
+
+	// Scanning must stay linear: ten times the input may take about ten times
+	// as long, not the hundred times a quadratic scan would. The faster of two
+	// runs is compared so a slow or instrumented machine does not matter.
+	fastest := func(line string) time.Duration {
+		best := time.Duration(math.MaxInt64)
+		for range 2 {
+			started := time.Now()
+			findPascalCaseImports(line)
+			best = min(best, time.Since(started))
+		}
+		return best
+	}
+	small, large := fastest(bundle(1000)), fastest(line)
+	if large > 30*small {
+		t.Errorf("findPascalCaseImports took %v on %d bytes but %v on %d bytes; want linear growth", small, len(bundle(1000)), large, len(line))
+	}
+}
+
+func TestAddRenameRejectsEmptyNames(t *testing.T) {
+	globalRenames = make(map[string]string)
+
+	tests := []struct {
+		name    string
+		oldName string
+		newName string
+	}{
+		{"empty old and new", "", ""},
+		{"empty new", "Button", ""},
+		{"empty old", "", "button"},
+		{"whitespace old", "  ", "button"},
+		{"whitespace new", "Button", " \t"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if addRename(tc.oldName, tc.newName) {
+				t.Errorf("addRename(%q, %q) = true; want false", tc.oldName, tc.newName)
+			}
+		})
+	}
+
+	if len(globalRenames) != 0 {
+		t.Errorf("globalRenames = %v; want no entries", globalRenames)
+	}
+}
+
 type testCase struct {
 	name     string
 	input    string
@@ -173,6 +436,17 @@ import { Dialog } from '@/components/ui/dialog'`,
 				"Dialog": "dialog",
 			},
 		},
+		{
+			name: "nuxt rootDir alias imports",
+			input: `import Button from '@@/components/ui/Button.vue'
+import { Input } from '~~/components/ui/Input'`,
+			expected: `import Button from '@@/components/ui/button.vue'
+import { Input } from '~~/components/ui/input'`,
+			renames: map[string]string{
+				"Button": "button",
+				"Input":  "input",
+			},
+		},
 		{
 			name: "relative path imports",
 			input: `import Card from '../Card.vue'
@@ -239,16 +513,198 @@ import { TabsList } from '@/components/ui/tabs-list'`,
 				"PopoverTrigger": "popover-trigger",
 			},
 		},
+		{
+			name: "component paths in ordinary strings",
+			input: `import { Button } from '@/components/ui/Button'
+const docs = '@/components/ui/Button'
+const file = "see @/components/ui/Button/Button.vue"`,
+			expected: `import { Button } from '@/components/ui/button'
+const docs = '@/components/ui/Button'
+const file = "see @/components/ui/Button/Button.vue"`,
+			renames: map[string]string{
+				"Button": "button",
+			},
+		},
+		{
+			name: "namespace imports",
+			input: `import * as Dialog from '@/components/ui/Dialog'
+import * as Ui from '@/components/ui/Sheet/Sheet.vue'
+
+const open = Dialog.Root`,
+			expected: `import * as Dialog from '@/components/ui/dialog'
+import * as Ui from '@/components/ui/sheet/sheet.vue'
+
+const open = Dialog.Root`,
+			renames: map[string]string{
+				"Dialog": "dialog",
+				"Sheet":  "sheet",
+			},
+		},
+		{
+			name: "query and hash suffixes",
+			input: `import buttonSource from '@/components/ui/Button.vue?raw'
+import cardUrl from './Card.vue?url'
+import Dialog from '@/components/ui/Dialog/Dialog.vue?component'
+import { Tabs } from '../Tabs#tabs'`,
+			expected: `import buttonSource from '@/components/ui/button.vue?raw'
+import cardUrl from './card.vue?url'
+import Dialog from '@/components/ui/dialog/dialog.vue?component'
+import { Tabs } from '../tabs#tabs'`,
+			renames: map[string]string{
+				"Button": "button",
+				"Card":   "card",
+				"Dialog": "dialog",
+				"Tabs":   "tabs",
+			},
+		},
+		{
+			name:     "mixed statements on one line",
+			input:    "import { Button } from '@/components/ui/Button'; import { cn } from '@/lib/utils'\nimport { cn as merge } from '@/lib/utils';import { buttonVariants } from '@/lib/Button';import Card from './Card.vue'",
+			expected: "import { Button } from '@/components/ui/button'; import { cn } from '@/lib/utils'\nimport { cn as merge } from '@/lib/utils';import { buttonVariants } from '@/lib/Button';import Card from './card.vue'",
+			renames: map[string]string{
+				"Button": "button",
+				"Card":   "card",
+			},
+		},
+		{
+			name: "imports without bindings",
+			input: `import {} from '@/components/ui/Button'
+import '@/components/ui/Button.vue'
+import "@/components/ui/Dialog/DialogContent.vue"
+import './Card.vue'`,
+			expected: `import {} from '@/components/ui/button'
+import '@/components/ui/button.vue'
+import "@/components/ui/dialog/dialog-content.vue"
+import './card.vue'`,
+			renames: map[string]string{
+				"Button":        "button",
+				"Card":          "card",
+				"Dialog":        "dialog",
+				"DialogContent": "dialog-content",
+			},
+		},
+		{
+			name: "whitespace inside the quotes",
+			input: `import Button from ' ./Button.vue '
+import { Card } from "  @/components/ui/Card"
+import Dialog from '@/components/ui/Dialog/Dialog.vue	'`,
+			expected: `import Button from ' ./button.vue '
+import { Card } from "  @/components/ui/card"
+import Dialog from '@/components/ui/dialog/dialog.vue	'`,
+			renames: map[string]string{
+				"Button": "button",
+				"Card":   "card",
+				"Dialog": "dialog",
+			},
+		},
+		{
+			name: "root ui barrel",
+			input: `import { Button, Dialog, DialogContent } from '@/components/ui'
+import { Card } from "@/components/ui/index"
+export { Button as UIButton } from '~/components/ui/index.ts'`,
+			expected: `import { Button, Dialog, DialogContent } from '@/components/ui'
+import { Card } from "@/components/ui/index"
+export { Button as UIButton } from '~/components/ui/index.ts'`,
+			renames: map[string]string{
+				"Button":        "button",
+				"Card":          "card",
+				"Dialog":        "dialog",
+				"DialogContent": "dialog-content",
+			},
+		},
+		{
+			name:     "CRLF multiline imports",
+			input:    "import {\r\n  Button,\r\n  ButtonGroup\r\n} from '@/components/ui/Button'\r\nimport {\r\n  Card\r\n} from './Card.vue'\r\n",
+			expected: "import {\r\n  Button,\r\n  ButtonGroup\r\n} from '@/components/ui/button'\r\nimport {\r\n  Card\r\n} from './card.vue'\r\n",
+			renames:  map[string]string{"Button": "button", "ButtonGroup": "button-group", "Card": "card"},
+		},
+		{
+			name: "type-only imports",
+			input: `import type { ButtonProps } from '@/components/ui/Button'
+import { type ButtonVariants, buttonVariants } from '@/components/ui/Button'
+export type { DialogContentProps } from './DialogContent.vue'
+import type Card from "./Card.vue"`,
+			expected: `import type { ButtonProps } from '@/components/ui/button'
+import { type ButtonVariants, buttonVariants } from '@/components/ui/button'
+export type { DialogContentProps } from './dialog-content.vue'
+import type Card from "./card.vue"`,
+			renames: map[string]string{
+				"Button":        "button",
+				"Card":          "card",
+				"DialogContent": "dialog-content",
+			},
+		},
+		{
+			name:     "from clause wrapped onto the next line",
+			input:    "import {\n  Button,\n} from\n'@/components/ui/Button'\nimport Dialog from\n  './Dialog.vue'\nexport { default as Dialog } from\r\n'./Dialog.vue'\nimport { DialogContent } from\n\t\"@/components/ui/Dialog/DialogContent\"",
+			expected: "import {\n  Button,\n} from\n'@/components/ui/button'\nimport Dialog from\n  './dialog.vue'\nexport { default as Dialog } from\r\n'./dialog.vue'\nimport { DialogContent } from\n\t\"@/components/ui/dialog/dialog-content\"",
+			renames: map[string]string{
+				"Button":        "button",
+				"Dialog":        "dialog",
+				"DialogContent": "dialog-content",
+			},
+		},
+		{
+			name:     "versioned ui folder",
+			input:    "import { Button } from '@/components/ui/v2/Button'\nimport Dialog from '@/components/ui/v2/Dialog/Dialog.vue'\nimport { DialogContent } from '~/components/ui/v2/Dialog/DialogContent'\nexport * from '@/components/ui/legacy/v2/Dialog'",
+			expected: "import { Button } from '@/components/ui/v2/button'\nimport Dialog from '@/components/ui/v2/dialog/dialog.vue'\nimport { DialogContent } from '~/components/ui/v2/dialog/dialog-content'\nexport * from '@/components/ui/legacy/v2/dialog'",
+			renames: map[string]string{
+				"Button":        "button",
+				"Dialog":        "dialog",
+				"DialogContent": "dialog-content",
+			},
+		},
+		{
+			name: "escaped quotes in stringified code",
+			input: `const source = 'import Button from \'./Button.vue\''
+{"script": "import { Dialog } from \"@/components/ui/Dialog\""}
+const lazy = 'import(\'./Card.vue\')'
+const plain = 'the ./Button.vue file'`,
+			expected: `const source = 'import Button from \'./button.vue\''
+{"script": "import { Dialog } from \"@/components/ui/dialog\""}
+const lazy = 'import(\'./card.vue\')'
+const plain = 'the ./Button.vue file'`,
+			renames: map[string]string{
+				"Button": "button",
+				"Card":   "card",
+				"Dialog": "dialog",
+			},
+		},
+		{
+			name: "commented-out imports",
+			input: `// import OldButton from './Button.vue'
+/* import { Card } from '@/components/ui/Card'
+   export { default as Card } from './Card.vue' */
+/** @see import Button from '@/components/ui/Button.vue' */
+<!-- import Card from './Card.vue' -->
+const docs = 'https://example.com/' // import Button from './Button.vue'
+import Button from './Button.vue'`,
+			expected: `// import OldButton from './Button.vue'
+/* import { Card } from '@/components/ui/Card'
+   export { default as Card } from './Card.vue' */
+/** @see import Button from '@/components/ui/Button.vue' */
+<!-- import Card from './Card.vue' -->
+const docs = 'https://example.com/' // import Button from './Button.vue'
+import Button from './button.vue'`,
+			renames: map[string]string{
+				"Button": "button",
+				"Card":   "card",
+			},
+		},
+		{
+			name:     "template literal path",
+			input:    "const loader = () => import(`@/components/ui/${name}/Button.vue`)\nconst other = `@/components/ui/${prefix}Button.vue`\nconst docs = `@/components/ui/Button`",
+			expected: "const loader = () => import(`@/components/ui/${name}/button.vue`)\nconst other = `@/components/ui/${prefix}Button.vue`\nconst docs = `@/components/ui/Button`",
+			renames: map[string]string{
+				"Button": "button",
+			},
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 
-			tmpDir, err := os.MkdirTemp("", "rename_test_*")
-			if err != nil {
-				t.Fatalf("Failed to create temp dir: %v", err)
-			}
-			defer os.RemoveAll(tmpDir)
+			tmpDir := t.TempDir()
 
 			tmpFile := filepath.Join(tmpDir, "test.vue")
 			if err := os.WriteFile(tmpFile, []byte(tc.input), 0644); err != nil {
@@ -274,11 +730,7 @@ import { TabsList } from '@/components/ui/tabs-list'`,
 }
 
 func TestIntegration(t *testing.T) {
-	tmpDir, err := os.MkdirTemp("", "rename_test_integration_*")
-	if err != nil {
-		t.Fatalf("Failed to create temp dir: %v", err)
-	}
-	defer os.RemoveAll(tmpDir)
+	tmpDir := t.TempDir()
 
 	componentsDir := filepath.Join(tmpDir, "components", "ui")
 	if err := os.MkdirAll(componentsDir, 0755); err != nil {
@@ -296,15 +748,7 @@ export default {}`,
 export default {}`,
 	}
 
-	for path, content := range files {
-		fullPath := filepath.Join(componentsDir, path)
-		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
-			t.Fatalf("Failed to create directory for %s: %v", path, err)
-		}
-		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
-			t.Fatalf("Failed to write file %s: %v", path, err)
-		}
-	}
+	writeFiles(t, componentsDir, files)
 
 	if err := buildRenameMap(componentsDir); err != nil {
 		t.Fatalf("buildRenameMap failed: %v", err)
@@ -327,3 +771,917 @@ export default {}`,
 		}
 	}
 }
+
+func TestExcludeComponents(t *testing.T) {
+	files := map[string]string{
+		"Button.vue": `import Slider from './Slider.vue'
+export default {}`,
+		"Slider.vue": `import Button from './Button.vue'
+export default {}`,
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = make(map[string]string)
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	excludeComponents([]string{"Slider"})
+
+	if _, exists := globalRenames["Slider"]; exists {
+		t.Fatalf("Slider still in rename map after exclusion: %v", globalRenames)
+	}
+
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "Slider.vue")); err != nil {
+		t.Errorf("Slider.vue should not have been renamed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "button.vue")); err != nil {
+		t.Errorf("Button.vue should have been renamed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "button.vue"))
+	if err != nil {
+		t.Fatalf("Failed to read button.vue: %v", err)
+	}
+	if string(content) != files["Button.vue"] {
+		t.Errorf("Import of excluded component was rewritten:\n%s", content)
+	}
+}
+
+func TestValidateComponentsDir(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	filePath := filepath.Join(tmpDir, "Button.vue")
+	if err := os.WriteFile(filePath, []byte(""), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if err := validateComponentsDir(tmpDir); err != nil {
+		t.Errorf("validateComponentsDir(%q) = %v; want nil", tmpDir, err)
+	}
+
+	missing := filepath.Join(tmpDir, "missing")
+	if err := validateComponentsDir(missing); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("validateComponentsDir(%q) = %v; want not found error", missing, err)
+	}
+
+	if err := validateComponentsDir(filePath); err == nil || !strings.Contains(err.Error(), "not a directory") {
+		t.Errorf("validateComponentsDir(%q) = %v; want not a directory error", filePath, err)
+	}
+
+	if os.Geteuid() == 0 {
+		t.Skip("permission checks are bypassed when running as root")
+	}
+	locked := filepath.Join(tmpDir, "locked")
+	if err := os.Mkdir(locked, 0000); err != nil {
+		t.Fatalf("Failed to create locked dir: %v", err)
+	}
+	defer os.Chmod(locked, 0755)
+
+	if err := validateComponentsDir(locked); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("validateComponentsDir(%q) = %v; want permission denied error", locked, err)
+	}
+}
+
+func TestRenameColocatedSiblings(t *testing.T) {
+	files := map[string]string{
+		"Button.vue": `export default {}`,
+		"Button.stories.ts": `import Button from './Button.vue'
+export default { component: Button }`,
+		"Button.stories.js": `import Button from './Button.vue'`,
+		"Card.vue": `import Button from './Button.vue'
+export default {}`,
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = make(map[string]string)
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "button.stories.ts"))
+	if err != nil {
+		t.Fatalf("Button.stories.ts was not renamed alongside Button.vue: %v", err)
+	}
+	expected := `import Button from './button.vue'
+export default { component: Button }`
+	if string(content) != expected {
+		t.Errorf("\nExpected:\n%s\n\nGot:\n%s", expected, content)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "Button.stories.ts")); !os.IsNotExist(err) {
+		t.Errorf("Button.stories.ts should no longer exist")
+	}
+
+	content, err = os.ReadFile(filepath.Join(tmpDir, "button.stories.js"))
+	if err != nil {
+		t.Fatalf("Button.stories.js was not renamed alongside Button.vue: %v", err)
+	}
+	if want := `import Button from './button.vue'`; string(content) != want {
+		t.Errorf("button.stories.js:\nExpected:\n%s\n\nGot:\n%s", want, content)
+	}
+}
+
+func TestStoryGlob(t *testing.T) {
+	previous := siblingSuffixes
+	defer func() { siblingSuffixes = previous }()
+
+	for _, glob := range []string{"Button.story.vue", "*", "*story.vue", "*.*.vue", "*/x.ts"} {
+		if _, err := storyGlobSuffixes([]string{glob}); err == nil {
+			t.Errorf("storyGlobSuffixes(%q) succeeded; want an error", glob)
+		}
+	}
+	suffixes, err := storyGlobSuffixes([]string{"*.story.vue", "*.examples.ts"})
+	if err != nil {
+		t.Fatalf("storyGlobSuffixes failed: %v", err)
+	}
+	siblingSuffixes = suffixes
+
+	files := map[string]string{
+		"Button.vue": `export default {}`,
+		"Button.story.vue": `<script setup lang="ts">
+import Button from './Button.vue'
+</script>`,
+		"Button.examples.ts": `export const examples = ['primary', 'ghost']`,
+		"Button.stories.ts":  `import Button from './Button.vue'`,
+		"Card.vue":           `import Button from './Button.vue'`,
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = make(map[string]string)
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"button.story.vue": `<script setup lang="ts">
+import Button from './button.vue'
+</script>`,
+		"button.examples.ts": files["Button.examples.ts"],
+		"Button.stories.ts":  `import Button from './button.vue'`,
+	}
+	for path, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tmpDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", path, want, content)
+		}
+	}
+}
+
+func TestSuffixedSiblingImports(t *testing.T) {
+	previous, previousStdout := siblingSuffixes, stdout
+	defer func() {
+		siblingSuffixes, stdout = previous, previousStdout
+		globalRenames = make(map[string]string)
+		componentIndex = nil
+	}()
+	siblingSuffixes = []string{".css"}
+	stdout = io.Discard
+
+	for _, indexed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("indexed=%v", indexed), func(t *testing.T) {
+			files := map[string]string{
+				"Button.vue": `export default {}`,
+				"Button.css": `.button {}`,
+				"Card.vue": `import './Button.css?inline'
+import raw from "./Button.css?raw"
+import url from './Button.css?url'
+import Button from './Button.vue'`,
+			}
+			tmpDir := writeTree(t, files)
+
+			componentIndex = nil
+			globalRenames = make(map[string]string)
+			if err := buildRenameMap(tmpDir); err != nil {
+				t.Fatalf("buildRenameMap failed: %v", err)
+			}
+			if indexed {
+				if err := indexComponents(tmpDir); err != nil {
+					t.Fatalf("indexComponents failed: %v", err)
+				}
+			}
+			if err := processFiles(tmpDir); err != nil {
+				t.Fatalf("processFiles failed: %v", err)
+			}
+
+			want := `import './button.css?inline'
+import raw from "./button.css?raw"
+import url from './button.css?url'
+import Button from './button.vue'`
+			content, err := os.ReadFile(filepath.Join(tmpDir, "Card.vue"))
+			if err != nil {
+				t.Fatalf("Failed to read Card.vue: %v", err)
+			}
+			if string(content) != want {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", want, content)
+			}
+			if _, err := os.Stat(filepath.Join(tmpDir, "button.css")); err != nil {
+				t.Errorf("Button.css was not renamed with its component: %v", err)
+			}
+		})
+	}
+}
+
+func TestBarrelFileKeepsName(t *testing.T) {
+	opts = options{keepBarrelNames: true}
+	defer func() { opts = options{} }()
+
+	files := map[string]string{
+		"Button/Button.vue": `export default {}`,
+		"Button/index.ts":   `export { default as Button } from './Button.vue'`,
+		"Card.vue": `import { Button } from './Button'
+export default {}`,
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = make(map[string]string)
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	barrel := filepath.Join(tmpDir, "button", "index.ts")
+	content, err := os.ReadFile(barrel)
+	if err != nil {
+		t.Fatalf("index.ts was renamed or lost: %v", err)
+	}
+	expected := `export { default as Button } from './button.vue'`
+	if string(content) != expected {
+		t.Errorf("\nExpected:\n%s\n\nGot:\n%s", expected, content)
+	}
+
+	if err := renamePath(barrel, filepath.Join(tmpDir, "button", "Index.ts")); err != nil {
+		t.Fatalf("renamePath failed: %v", err)
+	}
+	if _, err := os.Stat(barrel); err != nil {
+		t.Errorf("renamePath renamed barrel file %s", barrel)
+	}
+}
+
+// TestComponentAndBarrelSameName covers the usual shadcn layout, a
+// component file next to an index barrel exporting it under the same name:
+// both are updated once and nothing else is touched.
+func TestComponentAndBarrelSameName(t *testing.T) {
+	previous := stdout
+	defer func() {
+		stdout = previous
+		componentIndex = nil
+		globalRenames = make(map[string]string)
+	}()
+
+	for _, indexed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("indexed=%v", indexed), func(t *testing.T) {
+			files := map[string]string{
+				"Dialog/Dialog.vue": `<script setup lang="ts">
+import { DialogRoot } from 'reka-ui'
+</script>`,
+				"Dialog/index.ts": `export { default as Dialog } from './Dialog.vue'`,
+				"App.vue":         `import { Dialog } from './Dialog'`,
+			}
+			tmpDir := writeTree(t, files)
+
+			var out bytes.Buffer
+			stdout = &out
+			componentIndex = nil
+			globalRenames = make(map[string]string)
+			if err := buildRenameMap(tmpDir); err != nil {
+				t.Fatalf("buildRenameMap failed: %v", err)
+			}
+			if indexed {
+				if err := indexComponents(tmpDir); err != nil {
+					t.Fatalf("indexComponents failed: %v", err)
+				}
+			}
+			if err := processFiles(tmpDir); err != nil {
+				t.Fatalf("processFiles failed: %v", err)
+			}
+
+			expected := map[string]string{
+				"dialog/dialog.vue": files["Dialog/Dialog.vue"],
+				"dialog/index.ts":   `export { default as Dialog } from './dialog.vue'`,
+				"App.vue":           `import { Dialog } from './dialog'`,
+			}
+			for path, want := range expected {
+				content, err := os.ReadFile(filepath.Join(tmpDir, path))
+				if err != nil {
+					t.Fatalf("Failed to read %s: %v", path, err)
+				}
+				if string(content) != want {
+					t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", path, want, content)
+				}
+			}
+
+			entries, err := os.ReadDir(filepath.Join(tmpDir, "dialog"))
+			if err != nil {
+				t.Fatalf("Failed to read dialog folder: %v", err)
+			}
+			var names []string
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+			if want := []string{"dialog.vue", "index.ts"}; !slices.Equal(names, want) {
+				t.Errorf("dialog folder holds %v; want %v", names, want)
+			}
+
+			barrel := filepath.Join(tmpDir, "Dialog", "index.ts")
+			var edits, updates int
+			for _, line := range strings.Split(out.String(), "\n") {
+				if strings.HasPrefix(line, "Found ") && strings.Contains(line, barrel) {
+					edits++
+				}
+				if line == "Updated imports in: "+barrel {
+					updates++
+				}
+			}
+			if edits != 1 || updates != 1 {
+				t.Errorf("barrel edited %d time(s) and written %d time(s); want once each:\n%s", edits, updates, out.String())
+			}
+		})
+	}
+}
+
+func TestAstroImports(t *testing.T) {
+	files := map[string]string{
+		"Button.vue": `export default {}`,
+		"Page.astro": `---
+import Button from './Button.vue'
+---
+<Button client:load />`,
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = make(map[string]string)
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if globalRenames["Button"] != "button" {
+		t.Fatalf("Button was not detected from the .astro file: %v", globalRenames)
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "Page.astro"))
+	if err != nil {
+		t.Fatalf("Failed to read Page.astro: %v", err)
+	}
+	expected := `---
+import Button from './button.vue'
+---
+<Button client:load />`
+	if string(content) != expected {
+		t.Errorf("\nExpected:\n%s\n\nGot:\n%s", expected, content)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "button.vue")); err != nil {
+		t.Errorf("Button.vue was not renamed: %v", err)
+	}
+}
+
+func TestDeclarationFiles(t *testing.T) {
+	defer func() { globalRenames = make(map[string]string) }()
+
+	tmpDir := t.TempDir()
+
+	declarations := `declare module '@/components/ui/Button' {
+  import Button from '@/components/ui/Button/Button.vue'
+  export { Button }
+}
+
+declare module "@/components/ui/AlertDialog/AlertDialog.vue" {
+  import type { DefineComponent } from 'vue'
+  const component: DefineComponent
+  export default component
+}
+
+declare module '*.vue'
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "components.d.ts"), []byte(declarations), 0644); err != nil {
+		t.Fatalf("Failed to write components.d.ts: %v", err)
+	}
+
+	globalRenames = make(map[string]string)
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if globalRenames["Button"] != "button" || globalRenames["AlertDialog"] != "alert-dialog" {
+		t.Fatalf("components were not detected from the .d.ts file: %v", globalRenames)
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "components.d.ts"))
+	if err != nil {
+		t.Fatalf("Failed to read components.d.ts: %v", err)
+	}
+	expected := `declare module '@/components/ui/button' {
+  import Button from '@/components/ui/button/button.vue'
+  export { Button }
+}
+
+declare module "@/components/ui/alert-dialog/alert-dialog.vue" {
+  import type { DefineComponent } from 'vue'
+  const component: DefineComponent
+  export default component
+}
+
+declare module '*.vue'
+`
+	if string(content) != expected {
+		t.Errorf("\nExpected:\n%s\n\nGot:\n%s", expected, content)
+	}
+}
+
+func TestSubcomponentSplitModes(t *testing.T) {
+	tests := []struct {
+		name       string
+		noSubsplit bool
+		subsep     string
+		childFile  string
+		app        string
+		barrel     string
+	}{
+		{
+			name:      "split",
+			childFile: "dialog/dialog-content.vue",
+			app:       `import DialogContent from '@/components/ui/dialog/dialog-content.vue'`,
+			barrel:    `export { default as DialogContent } from './dialog-content.vue'`,
+		},
+		{
+			name:       "no subsplit",
+			noSubsplit: true,
+			childFile:  "dialog/dialogcontent.vue",
+			app:        `import DialogContent from '@/components/ui/dialog/dialogcontent.vue'`,
+			barrel:     `export { default as DialogContent } from './dialogcontent.vue'`,
+		},
+		{
+			name:      "dot separator",
+			subsep:    ".",
+			childFile: "dialog/dialog.content.vue",
+			app:       `import DialogContent from '@/components/ui/dialog/dialog.content.vue'`,
+			barrel:    `export { default as DialogContent } from './dialog.content.vue'`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts = options{noSubsplit: tc.noSubsplit, subsep: tc.subsep}
+			defer func() { opts = options{} }()
+
+			files := map[string]string{
+				"Dialog/Dialog.vue":        `export default {}`,
+				"Dialog/DialogContent.vue": `export default {}`,
+				"Dialog/index.ts":          `export { default as DialogContent } from './DialogContent.vue'`,
+				"App.vue":                  `import DialogContent from '@/components/ui/Dialog/DialogContent.vue'`,
+			}
+			tmpDir := writeTree(t, files)
+
+			globalRenames = map[string]string{"Dialog": "dialog", "DialogContent": toKebabCase("DialogContent")}
+			defer func() { globalRenames = make(map[string]string) }()
+			if tc.noSubsplit || tc.subsep != "" {
+				if err := joinSubcomponentNames(tmpDir); err != nil {
+					t.Fatalf("joinSubcomponentNames failed: %v", err)
+				}
+			}
+			if err := processFiles(tmpDir); err != nil {
+				t.Fatalf("processFiles failed: %v", err)
+			}
+
+			if _, err := os.Stat(filepath.Join(tmpDir, tc.childFile)); err != nil {
+				t.Errorf("expected %s to exist: %v", tc.childFile, err)
+			}
+			for path, want := range map[string]string{"App.vue": tc.app, "dialog/index.ts": tc.barrel} {
+				content, err := os.ReadFile(filepath.Join(tmpDir, path))
+				if err != nil {
+					t.Fatalf("Failed to read %s: %v", path, err)
+				}
+				if string(content) != want {
+					t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", path, want, content)
+				}
+			}
+		})
+	}
+}
+
+func TestDataTableFamilyBoundaries(t *testing.T) {
+	globalRenames = map[string]string{
+		"DataTable":       "data-table",
+		"DataTableRow":    "data-table-row",
+		"DataTableHeader": "data-table-header",
+	}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	input := `import DataTable from '@/components/ui/DataTable/DataTable.vue'
+import DataTableRow from '@/components/ui/DataTable/DataTableRow.vue'
+import { DataTableHeader } from '@/components/ui/DataTable/DataTableHeader'
+import { DataTableRow as Row } from '../DataTableRow'`
+	expected := `import DataTable from '@/components/ui/data-table/data-table.vue'
+import DataTableRow from '@/components/ui/data-table/data-table-row.vue'
+import { DataTableHeader } from '@/components/ui/data-table/data-table-header'
+import { DataTableRow as Row } from '../data-table-row'`
+
+	// Already-migrated kebab paths must be left alone, and data-table must not
+	// be treated as a prefix of data-table-row.
+	input += `
+import Cell from '@/components/ui/data-table/data-table-row.vue'`
+	expected += `
+import Cell from '@/components/ui/data-table/data-table-row.vue'`
+
+	// globalRenames is a map, so run enough times to cover every order in
+	// which the family's patterns can be applied.
+	for i := 0; i < 50; i++ {
+		if result := rewriteImports("test.vue", input); result != expected {
+			t.Fatalf("\nExpected:\n%s\n\nGot:\n%s", expected, result)
+		}
+	}
+}
+
+func TestDataTableChildExcluded(t *testing.T) {
+	globalRenames = map[string]string{"DataTable": "data-table"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	input := `import DataTableRow from '@/components/ui/DataTable/DataTableRow.vue'
+import DataTableContentCell from '@/components/ui/DataTable/DataTableContentCell.vue'`
+	expected := `import DataTableRow from '@/components/ui/data-table/DataTableRow.vue'
+import DataTableContentCell from '@/components/ui/data-table/DataTableContentCell.vue'`
+	if result := rewriteImports("test.vue", input); result != expected {
+		t.Errorf("\nExpected:\n%s\n\nGot:\n%s", expected, result)
+	}
+}
+
+func TestQuoteStyle(t *testing.T) {
+	globalRenames = map[string]string{"Button": "button", "Card": "card"}
+	defer func() {
+		globalRenames = make(map[string]string)
+		opts = options{}
+	}()
+
+	input := `import Button from "./Button.vue"
+import Card from './Card.vue'
+import { cn } from "@/lib/utils"`
+	tests := []struct {
+		quote    string
+		expected string
+	}{
+		{"preserve", `import Button from "./button.vue"
+import Card from './card.vue'
+import { cn } from "@/lib/utils"`},
+		{"single", `import Button from './button.vue'
+import Card from './card.vue'
+import { cn } from "@/lib/utils"`},
+		{"double", `import Button from "./button.vue"
+import Card from "./card.vue"
+import { cn } from "@/lib/utils"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.quote, func(t *testing.T) {
+			opts = options{quote: tc.quote}
+			if result := rewriteImports("test.vue", input); result != tc.expected {
+				t.Errorf("\nExpected:\n%s\n\nGot:\n%s", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestPartiallyMigratedFolder(t *testing.T) {
+	globalRenames = map[string]string{"Dialog": "dialog", "DialogContent": "dialog-content"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	input := `export { default as Dialog } from './Dialog.vue'
+import Dialog from '@/components/ui/dialog/Dialog.vue'
+import { DialogContent } from '~/components/ui/dialog/DialogContent'
+import Dialog from '../dialog/Dialog.vue'
+import DialogContent from './dialog/DialogContent.vue'`
+	expected := `export { default as Dialog } from './dialog.vue'
+import Dialog from '@/components/ui/dialog/dialog.vue'
+import { DialogContent } from '~/components/ui/dialog/dialog-content'
+import Dialog from '../dialog/dialog.vue'
+import DialogContent from './dialog/dialog-content.vue'`
+	if result := rewriteImports("test.vue", input); result != expected {
+		t.Errorf("\nExpected:\n%s\n\nGot:\n%s", expected, result)
+	}
+}
+
+func TestCompiledExtensions(t *testing.T) {
+	original, originalRewrite := componentExtensions, rewriteExtensions
+	componentExtensions = append([]string{}, ".vue", ".js", ".mjs")
+	rewriteExtensions = append([]string{}, ".vue", ".ts", ".astro", ".js", ".mjs")
+	defer func() { componentExtensions, rewriteExtensions = original, originalRewrite }()
+
+	files := map[string]string{
+		"Button.js":  `export default {}`,
+		"Dialog.mjs": `export default {}`,
+		"index.js": `export { default as Button } from './Button.js'
+export { default as Dialog } from "./Dialog.mjs"
+export { cn } from './utils.js'`,
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = make(map[string]string)
+	defer func() { globalRenames = make(map[string]string) }()
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "index.js"))
+	if err != nil {
+		t.Fatalf("Failed to read index.js: %v", err)
+	}
+	expected := `export { default as Button } from './button.js'
+export { default as Dialog } from "./dialog.mjs"
+export { cn } from './utils.js'`
+	if string(content) != expected {
+		t.Errorf("\nExpected:\n%s\n\nGot:\n%s", expected, content)
+	}
+	for _, name := range []string{"button.js", "dialog.mjs"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, name)); err != nil {
+			t.Errorf("expected %s to exist: %v", name, err)
+		}
+	}
+}
+
+func TestRewriteAndRenameExtensions(t *testing.T) {
+	original, originalRewrite := componentExtensions, rewriteExtensions
+	componentExtensions = normalizeExtensions([]string{"vue"})
+	rewriteExtensions = normalizeExtensions([]string{".vue", "ts"})
+	defer func() { componentExtensions, rewriteExtensions = original, originalRewrite }()
+	defer func() { globalRenames = make(map[string]string) }()
+
+	files := map[string]string{
+		"Button/Button.vue": `export default {}`,
+		"Button/Button.ts":  `export { default as Button } from './Button.vue'`,
+		"Button/index.ts":   `export { default as Button } from './Button.vue'`,
+		"Card.astro":        `import Button from './Button/Button.vue'`,
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = make(map[string]string)
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"button/button.vue": files["Button/Button.vue"],
+		"button/Button.ts":  `export { default as Button } from './button.vue'`,
+		"button/index.ts":   `export { default as Button } from './button.vue'`,
+		"Card.astro":        files["Card.astro"],
+	}
+	for path, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tmpDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", path, want, content)
+		}
+	}
+}
+
+func TestByteOrderMark(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	filePath := filepath.Join(tmpDir, "Card.vue")
+	input := utf8BOM + "import Button from './Button.vue'\nexport default {}"
+	if err := os.WriteFile(filePath, []byte(input), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	globalRenames = make(map[string]string)
+	defer func() { globalRenames = make(map[string]string) }()
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if globalRenames["Button"] != "button" {
+		t.Fatalf("Button was not detected on the BOM-prefixed first line: %v", globalRenames)
+	}
+	if err := updateFileContent(filePath); err != nil {
+		t.Fatalf("updateFileContent failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	expected := utf8BOM + "import Button from './button.vue'\nexport default {}"
+	if string(content) != expected {
+		t.Errorf("\nExpected:\n%q\n\nGot:\n%q", expected, content)
+	}
+}
+
+func TestSkipMarker(t *testing.T) {
+	opts = options{skipMarker: "@generated"}
+	defer func() { opts = options{} }()
+	globalRenames = map[string]string{"Button": "button"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"generated.ts": `// @generated by codegen, do not edit
+import Button from './Button.vue'`,
+		"App.vue": `import Button from './Button.vue'`,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, path), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file %s: %v", path, err)
+		}
+		if err := updateFileContent(filepath.Join(tmpDir, path)); err != nil {
+			t.Fatalf("updateFileContent failed: %v", err)
+		}
+	}
+
+	expected := map[string]string{
+		"generated.ts": files["generated.ts"],
+		"App.vue":      `import Button from './button.vue'`,
+	}
+	for path, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tmpDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", path, want, content)
+		}
+	}
+}
+
+func TestDetectedRenameTrimsWhitespace(t *testing.T) {
+	globalRenames = make(map[string]string)
+	defer func() { globalRenames = make(map[string]string) }()
+
+	if newName, ok := addDetectedRename("ButtonGroup\r"); !ok || newName != "button-group" {
+		t.Errorf("addDetectedRename(%q) = %q, %v; want %q", "ButtonGroup\r", newName, ok, "button-group")
+	}
+	if _, ok := globalRenames["ButtonGroup"]; !ok || len(globalRenames) != 1 {
+		t.Errorf("globalRenames = %q; want only ButtonGroup", globalRenames)
+	}
+}
+
+func TestDetectedIdentityRenamesSkipped(t *testing.T) {
+	globalRenames = make(map[string]string)
+	defer func() { globalRenames = make(map[string]string) }()
+
+	for _, name := range []string{"button", "alert-dialog", "Button"} {
+		addDetectedRename(name)
+	}
+	if len(globalRenames) != 1 || globalRenames["Button"] != "button" {
+		t.Errorf("globalRenames = %v; want only Button -> button", globalRenames)
+	}
+	for oldName, newName := range globalRenames {
+		if oldName == newName {
+			t.Errorf("identity mapping %s -> %s was added", oldName, newName)
+		}
+	}
+}
+
+func TestPrintProposalLimit(t *testing.T) {
+	globalRenames = map[string]string{"Badge": "badge", "Button": "button", "Card": "card", "Dialog": "dialog"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	tests := []struct {
+		name     string
+		limit    int
+		expected string
+	}{
+		{"no limit", 0, "Badge -> badge\nButton -> button\nCard -> card\nDialog -> dialog\n"},
+		{"truncated", 2, "Badge -> badge\nButton -> button\n...and 2 more\n"},
+		{"limit above count", 10, "Badge -> badge\nButton -> button\nCard -> card\nDialog -> dialog\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var output strings.Builder
+			printProposal(&output, tc.limit)
+			want := "\nProposed changes:\n=================\n" + tc.expected
+			if output.String() != want {
+				t.Errorf("Expected:\n%s\nGot:\n%s", want, output.String())
+			}
+		})
+	}
+}
+
+func TestPrintProposalGroupsFamilies(t *testing.T) {
+	globalRenames = map[string]string{
+		"Alert":              "alert",
+		"AlertDialog":        "alert-dialog",
+		"AlertDialogContent": "alert-dialog-content",
+		"Badge":              "badge",
+		"Sidebar":            "sidebar",
+		"SidebarContent":     "sidebar-content",
+		"SidebarFooter":      "sidebar-footer",
+	}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	tests := []struct {
+		name     string
+		limit    int
+		expected string
+	}{
+		{"no limit", 0, `Alert -> alert
+AlertDialog (2):
+  AlertDialog -> alert-dialog
+  AlertDialogContent -> alert-dialog-content
+Badge -> badge
+Sidebar (3):
+  Sidebar -> sidebar
+  SidebarContent -> sidebar-content
+  SidebarFooter -> sidebar-footer
+`},
+		{"truncated inside a family", 5, `Alert -> alert
+AlertDialog (2):
+  AlertDialog -> alert-dialog
+  AlertDialogContent -> alert-dialog-content
+Badge -> badge
+Sidebar (3):
+  Sidebar -> sidebar
+...and 2 more
+`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var output strings.Builder
+			printProposal(&output, tc.limit)
+			want := "\nProposed changes:\n=================\n" + tc.expected
+			if output.String() != want {
+				t.Errorf("Expected:\n%s\nGot:\n%s", want, output.String())
+			}
+		})
+	}
+}
+
+func TestStrictPascal(t *testing.T) {
+	tests := []struct {
+		name     string
+		strict   bool
+		prefixes []string
+		want     bool
+	}{
+		{"DialogContent", false, nil, true},
+		{"DialogContent", true, nil, true},
+		{"ButtonishThing", false, nil, true},
+		{"ButtonishThing", true, nil, false},
+		{"SidebarMenuSubButton", true, nil, true},
+		{"CardGrid", true, nil, false},
+		{"CardGrid", true, []string{"CardGrid"}, true},
+	}
+	for _, tc := range tests {
+		opts = options{strictPascal: tc.strict, prefixes: tc.prefixes}
+		original := componentPrefixes
+		componentPrefixes = append(componentPrefixes, tc.prefixes...)
+		if got := isPascalCase(tc.name); got != tc.want {
+			t.Errorf("isPascalCase(%q) with strict=%v prefixes=%v = %v; want %v", tc.name, tc.strict, tc.prefixes, got, tc.want)
+		}
+		componentPrefixes = original
+	}
+	opts = options{}
+}
+
+func TestUpdateSummary(t *testing.T) {
+	previous := rewriteExtensions
+	defer func() {
+		rewriteExtensions = previous
+		opts = options{}
+	}()
+
+	tests := []struct {
+		name       string
+		extensions []string
+		o          options
+		expected   string
+	}{
+		{"defaults", []string{".vue", ".ts", ".astro"}, options{outputCase: "kebab"},
+			"This will update all imports in .vue, .ts and .astro files to use the new kebab-case names."},
+		{"rewrite-ext and snake", []string{".vue", ".tsx"}, options{outputCase: "snake"},
+			"This will update all imports in .vue and .tsx files to use the new snake_case names."},
+		{"md and pascal", []string{".vue"}, options{outputCase: "pascal", markdown: true},
+			"This will update all imports in .vue, .md and .mdx files to use the new PascalCase names."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rewriteExtensions = tt.extensions
+			opts = tt.o
+			if got := updateSummary(); got != tt.expected {
+				t.Errorf("updateSummary() = %q; want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+func isMarkdownFile(name string) bool {
+	ext := filepath.Ext(name)
+	return ext == ".md" || ext == ".mdx"
+}
+
+var fenceOpenRegex = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+
+// rewriteMarkdownFences is --md: imports are rewritten inside fenced code
+// blocks only, so prose that mentions ./Button.vue is left as written. A
+// fence closes on a line of at least as many of the same character, or at
+// the end of the file if it is never closed.
+func rewriteMarkdownFences(filePath, content string) string {
+	lines := strings.SplitAfter(content, "\n")
+	var b strings.Builder
+	for i := 0; i < len(lines); i++ {
+		b.WriteString(lines[i])
+		match := fenceOpenRegex.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+		fence := match[1]
+		var code strings.Builder
+		for i++; i < len(lines); i++ {
+			if isFenceClose(lines[i], fence) {
+				break
+			}
+			code.WriteString(lines[i])
+		}
+		b.WriteString(rewriteTemplateLiterals(rewriteImports(filePath, code.String())))
+		if i < len(lines) {
+			b.WriteString(lines[i])
+		}
+	}
+	return b.String()
+}
+
+func isFenceClose(line, fence string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return false
+	}
+	run := len(trimmed) - len(strings.TrimLeft(trimmed, fence[:1]))
+	return run >= len(fence) && strings.TrimSpace(trimmed[run:]) == ""
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMarkdownCodeFences(t *testing.T) {
+	previous := stdout
+	stdout = io.Discard
+	defer func() { stdout = previous }()
+
+	if isSourceFile("README.md") {
+		t.Errorf("isSourceFile(%q) = true without --md", "README.md")
+	}
+	opts = options{markdown: true}
+	defer func() { opts = options{} }()
+	globalRenames = map[string]string{"Button": "button", "Dialog": "dialog"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	tmpDir := t.TempDir()
+
+	input := "# Button\n\n" +
+		"Import it from './Button.vue':\n\n" +
+		"```vue\n" +
+		"<script setup lang=\"ts\">\n" +
+		"import Button from './Button.vue'\n" +
+		"</script>\n" +
+		"```\n\n" +
+		"~~~ts\n" +
+		"import { Dialog } from '@/components/ui/Dialog'\n" +
+		"~~~\n\n" +
+		"````md\n" +
+		"```ts\n" +
+		"import Button from './Button.vue'\n" +
+		"```\n" +
+		"````\n\n" +
+		"import Button from './Button.vue' is prose here.\n"
+	expected := "# Button\n\n" +
+		"Import it from './Button.vue':\n\n" +
+		"```vue\n" +
+		"<script setup lang=\"ts\">\n" +
+		"import Button from './button.vue'\n" +
+		"</script>\n" +
+		"```\n\n" +
+		"~~~ts\n" +
+		"import { Dialog } from '@/components/ui/dialog'\n" +
+		"~~~\n\n" +
+		"````md\n" +
+		"```ts\n" +
+		"import Button from './button.vue'\n" +
+		"```\n" +
+		"````\n\n" +
+		"import Button from './Button.vue' is prose here.\n"
+
+	for _, name := range []string{"button.md", "button.mdx"} {
+		path := filepath.Join(tmpDir, name)
+		if !isSourceFile(name) {
+			t.Errorf("isSourceFile(%q) = false with --md", name)
+		}
+		if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+			t.Fatalf("Failed to write file %s: %v", name, err)
+		}
+		if err := updateFileContent(path); err != nil {
+			t.Fatalf("updateFileContent failed: %v", err)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if string(content) != expected {
+			t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", name, expected, content)
+		}
+	}
+
+	unclosed := "```ts\nimport Button from './Button.vue'\n"
+	if result := rewriteMarkdownFences("doc.md", unclosed); result != "```ts\nimport Button from './button.vue'\n" {
+		t.Errorf("unclosed fence:\n%s", result)
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"time"
+)
+
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+type options struct {
+	prefixes                stringList
+	excludes                stringList
+	acronyms                stringList
+	formatCmd               string
+	since                   string
+	resume                  bool
+	componentsJSON          string
+	emitScript              bool
+	useGitMv                bool
+	outputDir               string
+	maxChanges              int
+	force                   bool
+	reportFile              string
+	keepBarrelNames         bool
+	silentIfClean           bool
+	jsonPaths               stringList
+	noSubsplit              bool
+	dryRun                  bool
+	verify                  bool
+	relativeTo              string
+	quote                   string
+	assumeYesOnClean        bool
+	exts                    stringList
+	dirsOnly                bool
+	mapFile                 string
+	renameMapOut            string
+	debug                   string
+	subsep                  string
+	sfcBlocks               bool
+	parallel                int
+	yes                     bool
+	check                   bool
+	jsonOutput              bool
+	ci                      bool
+	skipMarker              string
+	tree                    bool
+	aliasTable              string
+	previewLimit            int
+	strictPascal            bool
+	patchFile               string
+	rewriteTags             bool
+	allowNativeTagCollision bool
+	followReexports         bool
+	fromCase                string
+	outputCase              string
+	noDefaultAcronyms       bool
+	traceFile               string
+	importsOnly             string
+	allowFile               string
+	markdown                bool
+	forceLock               bool
+	stripUIPrefix           bool
+	usage                   bool
+	dumpAST                 string
+	scope                   string
+	collapseToIndex         bool
+	entry                   string
+	followSymlinks          bool
+	emitCodemod             string
+	ignoreCaseInDetection   bool
+	retries                 int
+	storyGlobs              stringList
+	rewriteExts             stringList
+	renameExts              stringList
+	checkBarrel             bool
+	confirmMessage          string
+	confirmTimeout          time.Duration
+	confirmDefault          string
+	ioConcurrency           int
+}
+
+var opts options
+
+func parseArgs(args []string, output io.Writer) (options, []string, error) {
+	var o options
+
+	fs := flag.NewFlagSet("rename-shadcn-vue", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.Usage = func() {
+		fmt.Fprintln(output, "Usage: rename-shadcn-vue [flags] [components_directory]")
+		fs.PrintDefaults()
+	}
+	fs.Var(&o.prefixes, "prefix", "additional component prefix to recognize (repeatable)")
+	fs.Var(&o.excludes, "exclude", "component name to leave untouched even if detected (repeatable)")
+	fs.StringVar(&o.allowFile, "allow-file", "", "only rename the components listed in `path`, one name per line")
+	fs.StringVar(&o.since, "since", "", "only rewrite and rename files changed relative to this git ref, e.g. origin/main")
+	fs.StringVar(&o.scope, "scope", "", "build the rename map from the components directory but only rewrite and rename files under `dir`")
+	fs.StringVar(&o.entry, "entry", "", "detect components by following imports from the files matching `glob` instead of scanning the directory")
+	fs.BoolVar(&o.resume, "resume", false, "skip files recorded as processed by an interrupted run")
+	fs.StringVar(&o.componentsJSON, "components-json", "", "path to components.json, bypassing auto-discovery in the current directory")
+	fs.BoolVar(&o.emitScript, "emit-script", false, "print git mv commands for the file renames instead of renaming files")
+	fs.StringVar(&o.importsOnly, "imports-only", "", "rewrite imports but write the git mv commands for the renames to `file` instead of renaming")
+	fs.BoolVar(&o.useGitMv, "use-git-mv", false, "rename files with git mv so history is preserved, falling back to a plain rename")
+	fs.StringVar(&o.outputDir, "output-dir", "", "write the renamed and rewritten tree to this directory, leaving the source untouched")
+	fs.IntVar(&o.maxChanges, "max-changes", 0, "refuse to proceed if more than this many files would change (0 disables the check)")
+	fs.BoolVar(&o.force, "force", false, "proceed even if --max-changes is exceeded")
+	fs.BoolVar(&o.forceLock, "force-lock", false, "replace a lock left behind by a run that is no longer going")
+	fs.Var(&o.acronyms, "acronym", "acronym to keep as one word in kebab-case names, e.g. OAuth (repeatable)")
+	fs.BoolVar(&o.noDefaultAcronyms, "no-default-acronyms", false, "drop the built-in acronyms (UI, OAuth); only --acronym words are kept together")
+	fs.BoolVar(&o.stripUIPrefix, "strip-ui-prefix", false, "drop a leading ui word from derived names, so UIButton becomes button")
+	fs.StringVar(&o.reportFile, "report-file", "", "write a JSON report of the renames and changed files to this path")
+	fs.BoolVar(&o.keepBarrelNames, "keep-barrel-names", true, "never rename index.ts/index.js barrel files; their contents are still rewritten")
+	fs.BoolVar(&o.silentIfClean, "silent-if-clean", false, "print nothing and exit 0 when there is nothing to rename")
+	fs.Var(&o.jsonPaths, "json-paths", "glob of JSON files whose string values reference component paths to rewrite (repeatable)")
+	fs.BoolVar(&o.noSubsplit, "no-subsplit", false, "keep sub-components as one token after their parent, e.g. dialog/dialogcontent")
+	fs.BoolVar(&o.dryRun, "dry-run", false, "list the files that would change without touching disk")
+	fs.BoolVar(&o.verify, "verify", false, "with --dry-run, check that every rewritten import resolves after the rename")
+	fs.BoolVar(&o.checkBarrel, "check-barrel", false, "after the run, check that every relative re-export in a barrel points at a file on disk")
+	fs.StringVar(&o.relativeTo, "relative-to", "", "report and log file paths relative to this directory instead of the components root")
+	fs.StringVar(&o.quote, "quote", "preserve", "quote style for rewritten import specifiers: single, double or preserve")
+	fs.BoolVar(&o.assumeYesOnClean, "assume-yes-on-clean", false, "skip the confirmation prompt when the run only renames files and edits no content")
+	fs.Var(&o.exts, "ext", "extra component file extension for compiled trees, e.g. js or mjs (repeatable)")
+	fs.Var(&o.rewriteExts, "rewrite-ext", "extension of the files whose imports are rewritten, replacing the built-in vue, ts and astro (repeatable)")
+	fs.Var(&o.renameExts, "rename-ext", "extension of the component files that are renamed, replacing the built-in vue (repeatable)")
+	fs.BoolVar(&o.dirsOnly, "dirs-only", false, "rename only PascalCase folders and the folder segments of imports, keeping file names")
+	fs.BoolVar(&o.collapseToIndex, "collapse-to-index", false, "rename a component file that is alone in a folder of the same name to index.vue")
+	fs.BoolVar(&o.followSymlinks, "follow-symlinks", false, "rewrite and rename the files symlinked component files point at instead of skipping the links")
+	fs.StringVar(&o.mapFile, "map", "", "JSON file of old -> new component names to use in addition to, and over, the detected ones")
+	fs.StringVar(&o.renameMapOut, "rename-map-out", "", "write the computed old -> new component name map to this JSON file")
+	fs.StringVar(&o.emitCodemod, "emit-codemod", "", "write the renames and the import specifier changes made to this JSON file, for replaying them with another codemod tool")
+	fs.StringVar(&o.subsep, "subsep", "-", "separator between a parent and its sub-component in derived names, e.g. . for dialog/dialog.content")
+	fs.Var(&o.storyGlobs, "story-glob", "sibling file pattern to rename with its component, e.g. *.story.vue; replaces the built-in *.stories.ts, *.stories.js, *.test.ts and *.spec.ts (repeatable)")
+	fs.BoolVar(&o.sfcBlocks, "sfc-blocks", false, "rewrite src=\"./Name.md\" in SFC custom blocks and rename those sibling files with their component")
+	fs.BoolVar(&o.markdown, "md", false, "also rewrite imports inside fenced code blocks in .md and .mdx files")
+	fs.IntVar(&o.parallel, "parallel", runtime.NumCPU(), "number of files to rewrite at once; 1 processes them one by one")
+	fs.IntVar(&o.ioConcurrency, "io-concurrency", defaultIOConcurrency, "most file reads, writes and renames to have in flight at once, whatever --parallel is")
+	fs.BoolVar(&o.yes, "yes", false, "apply the changes without asking for confirmation")
+	fs.StringVar(&o.confirmMessage, "confirm-message", "Do you want to proceed with these changes?", "question asked before applying the changes")
+	fs.DurationVar(&o.confirmTimeout, "confirm-timeout", 0, "answer the confirmation prompt with --confirm-default if nothing is typed within `duration`, e.g. 30s")
+	fs.StringVar(&o.confirmDefault, "confirm-default", "n", "answer used when --confirm-timeout runs out: y or n")
+	fs.BoolVar(&o.check, "check", false, "list the files that would change and exit 1 if there are any, without touching disk")
+	fs.BoolVar(&o.jsonOutput, "json", false, "print the JSON report to stdout instead of the log")
+	fs.BoolVar(&o.ci, "ci", false, "non-interactive CI mode; implies --yes --check --json unless they are given explicitly")
+	fs.StringVar(&o.skipMarker, "skip-marker", "", "never rewrite files whose content contains this string, e.g. @generated")
+	fs.BoolVar(&o.tree, "tree", false, "print the components directory with each planned rename before asking to proceed")
+	fs.BoolVar(&o.usage, "usage", false, "list the files that use each renamed component in h() render calls or TSX tags; also rewrites imports in .tsx and .jsx files")
+	fs.StringVar(&o.aliasTable, "aliases", "", "JSON file mapping directory globs to the import aliases that point at them, e.g. {\"src/components/app\": \"@app\"}")
+	fs.IntVar(&o.previewLimit, "preview-limit", 0, "show at most this many proposed renames before the prompt (0 shows all); all are still applied")
+	fs.BoolVar(&o.strictPascal, "strict-pascal", false, "only rename canonical shadcn-vue components and sub-components, not every name with a known prefix")
+	fs.StringVar(&o.patchFile, "patch", "", "write the changes as a git patch to this file instead of applying them")
+	fs.BoolVar(&o.rewriteTags, "rewrite-tags", false, "also write PascalCase component tags in templates in kebab-case, e.g. <DialogContent> as <dialog-content>")
+	fs.BoolVar(&o.allowNativeTagCollision, "allow-native-tag-collision", false, "with --rewrite-tags, also rewrite tags whose kebab-case form is a native HTML element, e.g. <Table>")
+	fs.BoolVar(&o.followReexports, "follow-reexports", false, "also rename components and folders reached through re-export chains from the root barrel, whatever their names")
+	fs.StringVar(&o.fromCase, "from-case", "pascal", "case the component names are in now: pascal, kebab or snake")
+	fs.StringVar(&o.outputCase, "case", "kebab", "case to rename components to: pascal, kebab, snake or flat")
+	fs.BoolVar(&o.ignoreCaseInDetection, "ignore-case-in-detection", false, "also rewrite import paths that spell a component name with different letter case, e.g. ./alertDialog.vue")
+	fs.StringVar(&o.traceFile, "trace-file", "", "write every file read and write, isPascalCase decision and pattern tried to `path`")
+	fs.IntVar(&o.retries, "retries", 3, "retry a write or rename this many times, with backoff, when it fails with a transient error such as EBUSY")
+	fs.StringVar(&o.debug, "debug", "", "log every pattern tried against this file's imports and whether it matched")
+	fs.StringVar(&o.dumpAST, "dump-ast", "", "print how `file` is tokenized, the import statements found in it and the components detected, then exit")
+	fs.StringVar(&o.formatCmd, "format-cmd", "", "command to run on changed files after a successful run, e.g. \"pnpm prettier --write\"")
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return o, nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	// Flags given explicitly win over the ones --ci implies, so e.g.
+	// --ci --check=false applies the changes without prompting.
+	if o.ci {
+		given := make(map[string]bool)
+		fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+		for _, name := range []string{"yes", "check", "json"} {
+			if !given[name] {
+				fs.Set(name, "true")
+			}
+		}
+	}
+
+	return o, positional, nil
+}
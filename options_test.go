@@ -0,0 +1,57 @@
+package main
+
+import (
+	"io"
+	"testing"
+)
+
+func TestParseArgsPrefix(t *testing.T) {
+	o, args, err := parseArgs([]string{"--prefix", "Chart", "src/components/ui", "--prefix", "Map"}, io.Discard)
+	if err != nil {
+		t.Fatalf("parseArgs failed: %v", err)
+	}
+
+	if len(o.prefixes) != 2 || o.prefixes[0] != "Chart" || o.prefixes[1] != "Map" {
+		t.Errorf("prefixes = %v; want [Chart Map]", o.prefixes)
+	}
+	if len(args) != 1 || args[0] != "src/components/ui" {
+		t.Errorf("args = %v; want [src/components/ui]", args)
+	}
+}
+
+func TestCustomPrefixRecognized(t *testing.T) {
+	original := componentPrefixes
+	defer func() { componentPrefixes = original }()
+
+	if isPascalCase("ChartLegend") {
+		t.Fatalf("isPascalCase(%q) = true before adding prefix", "ChartLegend")
+	}
+
+	o, _, err := parseArgs([]string{"--prefix", "Chart"}, io.Discard)
+	if err != nil {
+		t.Fatalf("parseArgs failed: %v", err)
+	}
+	componentPrefixes = append(componentPrefixes, o.prefixes...)
+
+	if !isPascalCase("ChartLegend") {
+		t.Errorf("isPascalCase(%q) = false; want true with --prefix Chart", "ChartLegend")
+	}
+}
+
+func TestParseArgsCI(t *testing.T) {
+	o, _, err := parseArgs([]string{"--ci"}, io.Discard)
+	if err != nil {
+		t.Fatalf("parseArgs failed: %v", err)
+	}
+	if !o.yes || !o.check || !o.jsonOutput {
+		t.Errorf("--ci gave yes=%v check=%v json=%v; want all true", o.yes, o.check, o.jsonOutput)
+	}
+
+	o, _, err = parseArgs([]string{"--ci", "--check=false"}, io.Discard)
+	if err != nil {
+		t.Fatalf("parseArgs failed: %v", err)
+	}
+	if !o.yes || o.check || !o.jsonOutput {
+		t.Errorf("--ci --check=false gave yes=%v check=%v json=%v; want check off only", o.yes, o.check, o.jsonOutput)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutputDirLeavesSourceUntouched(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	srcDir := filepath.Join(tmpDir, "src", "components", "ui")
+	outDir := filepath.Join(tmpDir, "preview")
+
+	files := map[string]string{
+		"Card.vue":          `import Dialog from './Dialog/Dialog.vue'`,
+		"Dialog/Dialog.vue": `export default {}`,
+		"Dialog/index.ts":   `export { default as Dialog } from './Dialog.vue'`,
+	}
+	writeFiles(t, srcDir, files)
+
+	if err := prepareOutputDir(srcDir, filepath.Join(srcDir, "out")); err == nil {
+		t.Errorf("prepareOutputDir should reject an output directory inside the source")
+	}
+	if err := prepareOutputDir(srcDir, tmpDir); err == nil {
+		t.Errorf("prepareOutputDir should reject an output directory containing the source")
+	}
+
+	globalRenames = make(map[string]string)
+	if err := buildRenameMap(srcDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if err := prepareOutputDir(srcDir, outDir); err != nil {
+		t.Fatalf("prepareOutputDir failed: %v", err)
+	}
+	if err := copyTree(srcDir, outDir); err != nil {
+		t.Fatalf("copyTree failed: %v", err)
+	}
+	if err := processFiles(outDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	for path, content := range files {
+		result, err := os.ReadFile(filepath.Join(srcDir, path))
+		if err != nil {
+			t.Errorf("source file %s was moved: %v", path, err)
+			continue
+		}
+		if string(result) != content {
+			t.Errorf("source file %s was modified:\n%s", path, result)
+		}
+	}
+
+	result, err := os.ReadFile(filepath.Join(outDir, "dialog", "index.ts"))
+	if err != nil {
+		t.Fatalf("expected renamed barrel in output: %v", err)
+	}
+	if !strings.Contains(string(result), "'./dialog.vue'") {
+		t.Errorf("output barrel was not rewritten:\n%s", result)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "dialog", "dialog.vue")); err != nil {
+		t.Errorf("expected renamed component in output: %v", err)
+	}
+
+	if err := prepareOutputDir(srcDir, outDir); err == nil || !strings.Contains(err.Error(), "not empty") {
+		t.Errorf("prepareOutputDir() error = %v; want not empty error", err)
+	}
+}
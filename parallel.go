@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sync"
+)
+
+// With --parallel N above 1, file contents are rewritten by a pool of N
+// workers before anything is renamed. Each file's log lines are held while
+// the workers run and printed afterwards in path order, so the output is the
+// same from run to run whatever order the workers finish in.
+var (
+	fileLogs          map[string]*bytes.Buffer
+	fileLogsMu        sync.Mutex
+	contentsRewritten bool
+)
+
+// logf prints a line about filePath, or holds it until the workers finish.
+func logf(filePath, format string, args ...any) {
+	fileLogsMu.Lock()
+	defer fileLogsMu.Unlock()
+
+	if fileLogs == nil {
+		fmt.Fprintf(stdout, format, args...)
+		return
+	}
+	buf, ok := fileLogs[filePath]
+	if !ok {
+		buf = &bytes.Buffer{}
+		fileLogs[filePath] = buf
+	}
+	fmt.Fprintf(buf, format, args...)
+}
+
+func updateContentsParallel(dir string, workers int) error {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isSourceFile(d.Name()) || !inFileFilter(path) {
+			return nil
+		}
+		if activeCheckpoint.isDone(path) {
+			fmt.Fprintf(stdout, "Skipping already processed file: %s\n", displayPath(path))
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fileLogs = make(map[string]*bytes.Buffer)
+	jobs := make(chan int)
+	done := make(chan int)
+	errs := make([]error, len(paths))
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = updateFileContent(paths[i])
+				done <- i
+			}
+		}()
+	}
+	go func() {
+		for i := range paths {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(done)
+	}()
+
+	// The checkpoint is only written from this goroutine.
+	var checkpointErr error
+	for i := range done {
+		if errs[i] == nil && checkpointErr == nil {
+			checkpointErr = activeCheckpoint.markDone(paths[i])
+		}
+	}
+
+	logs := fileLogs
+	fileLogs = nil
+	for i, path := range paths {
+		if buf, ok := logs[path]; ok {
+			stdout.Write(buf.Bytes())
+		}
+		if errs[i] != nil {
+			recordFailure(path, errs[i])
+		}
+	}
+	if checkpointErr != nil {
+		return checkpointErr
+	}
+	contentsRewritten = true
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeParallelFixture(t *testing.T) string {
+	t.Helper()
+	files := map[string]string{
+		"Dialog/Dialog.vue":        `export default {}`,
+		"Dialog/DialogContent.vue": `export default {}`,
+		"Dialog/index.ts": `export { default as Dialog } from './Dialog.vue'
+export { default as DialogContent } from './DialogContent.vue'`,
+		"Button/Button.vue": `<script setup>
+import { Dialog } from '@/components/ui/Dialog'
+</script>`,
+		"Button/index.ts": `export { default as Button } from './Button.vue'`,
+	}
+	for i := range 20 {
+		files[fmt.Sprintf("pages/Page%d.vue", i)] = `import { Button } from '@/components/ui/Button'
+import DialogContent from '@/components/ui/Dialog/DialogContent.vue'`
+	}
+	return writeTree(t, files)
+}
+
+// runParallelFixture applies the renames to a fresh fixture and returns the
+// resulting tree and the log.
+func runParallelFixture(t *testing.T, workers int) (map[string]string, string) {
+	t.Helper()
+	tmpDir := writeParallelFixture(t)
+
+	var output bytes.Buffer
+	original := stdout
+	stdout = &output
+	defer func() { stdout = original }()
+	pathBase = tmpDir
+	defer func() { pathBase = "" }()
+	defer func() { contentsRewritten = false }()
+
+	if workers > 1 {
+		if err := updateContentsParallel(tmpDir, workers); err != nil {
+			t.Fatalf("updateContentsParallel failed: %v", err)
+		}
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	tree := make(map[string]string)
+	err := filepath.WalkDir(tmpDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		tree[planPath(tmpDir, path)] = string(content)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to read tree: %v", err)
+	}
+	return tree, output.String()
+}
+
+func TestParallelMatchesSerial(t *testing.T) {
+	globalRenames = map[string]string{"Dialog": "dialog", "DialogContent": "dialog-content", "Button": "button"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	serial, _ := runParallelFixture(t, 1)
+	parallel, firstLog := runParallelFixture(t, 4)
+
+	if len(serial) != len(parallel) {
+		t.Fatalf("serial run produced %d files, parallel %d", len(serial), len(parallel))
+	}
+	for path, want := range serial {
+		if got, ok := parallel[path]; !ok || got != want {
+			t.Errorf("%s:\nSerial:\n%s\n\nParallel:\n%s", path, want, got)
+		}
+	}
+
+	for range 3 {
+		if _, log := runParallelFixture(t, 4); log != firstLog {
+			t.Fatalf("parallel log changed between runs:\n%s\n\nvs:\n%s", firstLog, log)
+		}
+	}
+}
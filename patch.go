@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// writePatch writes the planned run as a patch for `git apply`: every file
+// whose content or path would change gets a git diff, with rename headers
+// for moved files. Nothing under dir is modified.
+func writePatch(path, dir string) error {
+	p, err := buildPlan(dir)
+	if err != nil {
+		return err
+	}
+	edits := make(map[string]string)
+	for _, edit := range p.Edits {
+		edits[edit.Path] = edit.Content
+	}
+
+	root, err := patchRoot(dir)
+	if err != nil {
+		return err
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if resolved, err := filepath.EvalSymlinks(absDir); err == nil {
+		absDir = resolved
+	}
+
+	var out strings.Builder
+	err = filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || isToolFile(d.Name()) {
+			return nil
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		rel := planPath(dir, file)
+		newContent, edited := edits[rel]
+		if !edited {
+			newContent = string(content)
+		}
+		from := patchPath(root, filepath.Join(absDir, filepath.FromSlash(rel)))
+		to := patchPath(root, filepath.Join(absDir, filepath.FromSlash(planPath(dir, plannedPath(dir, file)))))
+		if from == to && !edited {
+			return nil
+		}
+
+		fmt.Fprintf(&out, "diff --git a/%s b/%s\n", from, to)
+		if from != to {
+			if !edited {
+				out.WriteString("similarity index 100%\n")
+			}
+			fmt.Fprintf(&out, "rename from %s\nrename to %s\n", from, to)
+		}
+		if edited {
+			fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", from, to)
+			out.WriteString(unifiedHunks(string(content), newContent))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(out.String()), 0644)
+}
+
+// patchRoot is the directory patch paths are relative to: the top of the git
+// work tree when dir is inside one, as `git apply` expects, and the current
+// directory otherwise.
+func patchRoot(dir string) (string, error) {
+	if topLevel, err := runGit(dir, "rev-parse", "--show-toplevel"); err == nil {
+		return strings.TrimSpace(string(topLevel)), nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(cwd); err == nil {
+		cwd = resolved
+	}
+	return cwd, nil
+}
+
+func patchPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
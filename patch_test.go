@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestPatchAppliesCleanly(t *testing.T) {
+	tmpDir := t.TempDir()
+	initGitRepo(t, tmpDir)
+
+	files := map[string]string{
+		"ui/Dialog/Dialog.vue":        "<template>\n  <div />\n</template>\n",
+		"ui/Dialog/DialogContent.vue": "<script setup>\nimport { cn } from '@/lib/utils'\n</script>\n",
+		"ui/Dialog/index.ts": `export { default as Dialog } from './Dialog.vue'
+export { default as DialogContent } from './DialogContent.vue'`,
+		"ui/Button.vue": "<script setup>\nimport { Dialog } from '@/components/ui/Dialog'\nconst a = 1\nconst b = 2\nconst c = 3\nconst d = 4\nconst e = 5\nconst f = 6\nconst g = 7\nimport Icon from './Icon.vue'\n</script>\n",
+	}
+	writeFiles(t, tmpDir, files)
+	gitCommitAll(t, tmpDir)
+
+	globalRenames = map[string]string{"Dialog": "dialog", "DialogContent": "dialog-content", "Button": "button", "Icon": "icon"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	patchFile := filepath.Join(tmpDir, "changes.patch")
+	if err := writePatch(patchFile, filepath.Join(tmpDir, "ui")); err != nil {
+		t.Fatalf("writePatch failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "ui", "Dialog", "Dialog.vue")); err != nil {
+		t.Fatalf("writePatch touched the tree: %v", err)
+	}
+
+	cmd := exec.Command("git", "apply", "changes.patch")
+	cmd.Dir = tmpDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		patch, _ := os.ReadFile(patchFile)
+		t.Fatalf("git apply failed: %v\n%s\n%s", err, output, patch)
+	}
+
+	expected := map[string]string{
+		"ui/dialog/dialog.vue":         files["ui/Dialog/Dialog.vue"],
+		"ui/dialog/dialog-content.vue": files["ui/Dialog/DialogContent.vue"],
+		"ui/dialog/index.ts": `export { default as Dialog } from './dialog.vue'
+export { default as DialogContent } from './dialog-content.vue'`,
+		"ui/button.vue": "<script setup>\nimport { Dialog } from '@/components/ui/dialog'\nconst a = 1\nconst b = 2\nconst c = 3\nconst d = 4\nconst e = 5\nconst f = 6\nconst g = 7\nimport Icon from './icon.vue'\n</script>\n",
+	}
+	for path, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tmpDir, path))
+		if err != nil {
+			t.Errorf("Failed to read %s: %v", path, err)
+			continue
+		}
+		if string(content) != want {
+			t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", path, want, content)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "ui", "Dialog")); err == nil {
+		t.Error("ui/Dialog still exists after applying the patch")
+	}
+}
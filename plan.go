@@ -0,0 +1,487 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// plan records exactly what a dry run would do, so `apply --plan` can
+// replay it later without re-scanning. Paths are relative to Root. Sources
+// holds the hash of every file the dry run scanned, edited or not, since a
+// file that gained an import after the plan was made would otherwise keep
+// it pointing at a renamed path.
+// Conflicts are renames whose target is already taken; warnings are things
+// the run leaves alone that a reviewer may want to know about.
+type RenamePlan struct {
+	Root      string            `json:"root"`
+	Edits     []planEdit        `json:"edits"`
+	Renames   []pathRename      `json:"renames"`
+	Sources   map[string]string `json:"sources"`
+	Conflicts []string          `json:"conflicts,omitempty"`
+	Warnings  []string          `json:"warnings,omitempty"`
+}
+
+// planEdit is one file's rewrite. Changes are its byte ranges in the file as
+// hashed; a tool building on the plan can drop some of them to apply the
+// rest, and apply rebuilds the file from the ones left. Content is the whole
+// rewritten file, for display only.
+type planEdit struct {
+	Path    string       `json:"path"`
+	SHA256  string       `json:"sha256"`
+	Content string       `json:"content"`
+	Changes []planChange `json:"changes,omitempty"`
+}
+
+// planChange replaces Old, the Length bytes from Start to End, with New.
+// Length is End - Start, spelled out for editors that want an offset and a
+// length; apply reads only Start and End.
+type planChange struct {
+	Start  int    `json:"start"`
+	End    int    `json:"end"`
+	Length int    `json:"length"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+}
+
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func buildPlan(dir string) (*RenamePlan, error) {
+	previous := stdout
+	stdout = io.Discard
+	defer func() { stdout = previous }()
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	p := &RenamePlan{Root: root, Edits: []planEdit{}, Renames: []pathRename{}, Sources: map[string]string{}}
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isSourceFile(d.Name()) || !inFileFilter(path) {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		p.Sources[planPath(dir, path)] = hashContent(content)
+		if rewritten := rewriteContent(path, string(content)); rewritten != string(content) {
+			p.Edits = append(p.Edits, planEdit{
+				Path:    planPath(dir, path),
+				SHA256:  hashContent(content),
+				Content: rewritten,
+				Changes: contentChanges(string(content), rewritten),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(p.Edits, func(i, j int) bool { return p.Edits[i].Path < p.Edits[j].Path })
+
+	renames, err := plannedRenames(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, rename := range renames {
+		p.Renames = append(p.Renames, pathRename{From: planPath(dir, rename.From), To: planPath(dir, rename.To)})
+		if _, err := os.Stat(rename.To); err == nil && !sameFile(rename.From, rename.To) {
+			p.Conflicts = append(p.Conflicts, fmt.Sprintf("%s: %s already exists", planPath(dir, rename.From), planPath(dir, rename.To)))
+		}
+		if isSymlink(rename.From) && !opts.followSymlinks {
+			p.Warnings = append(p.Warnings, fmt.Sprintf("%s is a symlink; the file it points at is not renamed", planPath(dir, rename.From)))
+		}
+	}
+	return p, nil
+}
+
+// Plan builds the rename map for dir under o and plans the run without
+// touching disk, the way a UI built on the planning phase would call it.
+// Each call starts from o alone, whatever an earlier run left behind.
+func Plan(dir string, o options) (*RenamePlan, error) {
+	previous := stdout
+	stdout = io.Discard
+	defer func() { stdout = previous }()
+
+	resetRunState(o)
+	if err := buildRenameMap(dir); err != nil {
+		return nil, err
+	}
+	if err := indexComponents(dir); err != nil {
+		return nil, err
+	}
+	return buildPlan(dir)
+}
+
+// resetRunState clears the package state a run builds up, so a second Plan
+// in the same process does not see the first one's index, scope or filter.
+func resetRunState(o options) {
+	opts = o
+	globalRenames = make(map[string]string)
+	componentIndex = nil
+	componentRoot = ""
+	collapsedDirs = nil
+	scopeRoot = ""
+	fileFilter = nil
+	aliasMappings = nil
+	uiAliases = nil
+	configUIDir = ""
+}
+
+// sameFile reports whether two paths are one file, as a case-only rename
+// on a case-insensitive file system sees them.
+func sameFile(a, b string) bool {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	infoB, err := os.Stat(b)
+	return err == nil && os.SameFile(infoA, infoB)
+}
+
+// contentChanges lists where rewritten differs from content. Rewrites keep
+// the line count, so each changed line gives its changes; otherwise the
+// whole differing middle is one change.
+func contentChanges(content, rewritten string) []planChange {
+	oldLines := strings.SplitAfter(content, "\n")
+	newLines := strings.SplitAfter(rewritten, "\n")
+	if len(oldLines) != len(newLines) {
+		return []planChange{diffChange(content, rewritten, 0)}
+	}
+
+	var changes []planChange
+	offset := 0
+	for i, line := range oldLines {
+		if line != newLines[i] {
+			changes = append(changes, lineChanges(line, newLines[i], offset)...)
+		}
+		offset += len(line)
+	}
+	return changes
+}
+
+// lineChanges splits a changed line into words and the text between them.
+// Rewrites replace words one for one (Dialog/DialogContent becomes
+// dialog/dialog-content), so when both lines split into as many pieces each
+// run of differing pieces is its own change; otherwise the line gives one
+// change trimmed to the part that differs.
+func lineChanges(a, b string, offset int) []planChange {
+	oldPieces, newPieces := wordPieces(a), wordPieces(b)
+	if len(oldPieces) != len(newPieces) {
+		return []planChange{diffChange(a, b, offset)}
+	}
+
+	var changes []planChange
+	start := offset
+	for i := 0; i < len(oldPieces); i++ {
+		if oldPieces[i] == newPieces[i] {
+			start += len(oldPieces[i])
+			continue
+		}
+		change := planChange{Start: start}
+		for ; i < len(oldPieces) && oldPieces[i] != newPieces[i]; i++ {
+			change.Old += oldPieces[i]
+			change.New += newPieces[i]
+		}
+		change.End = change.Start + len(change.Old)
+		change.Length = len(change.Old)
+		changes = append(changes, change)
+		if i < len(oldPieces) {
+			start = change.End + len(oldPieces[i])
+		}
+	}
+	return changes
+}
+
+// wordPieces splits s into alternating runs of word and other bytes.
+func wordPieces(s string) []string {
+	var pieces []string
+	for start := 0; start < len(s); {
+		end := start + 1
+		for end < len(s) && isWordByte(s[end]) == isWordByte(s[start]) {
+			end++
+		}
+		pieces = append(pieces, s[start:end])
+		start = end
+	}
+	return pieces
+}
+
+// diffChange trims the common prefix and suffix of a and b and returns what
+// is left as a change at offset, widened to whole words so a change reads
+// Dialog -> dialog rather than D -> d.
+func diffChange(a, b string, offset int) planChange {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	for prefix > 0 && isWordByte(a[prefix-1]) {
+		prefix--
+	}
+	for suffix > 0 && isWordByte(a[len(a)-suffix]) {
+		suffix--
+	}
+	return planChange{
+		Start:  offset + prefix,
+		End:    offset + len(a) - suffix,
+		Length: len(a) - suffix - prefix,
+		Old:    a[prefix : len(a)-suffix],
+		New:    b[prefix : len(b)-suffix],
+	}
+}
+
+func isWordByte(c byte) bool {
+	return c == '-' || c == '_' || c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+}
+
+// applyChanges applies changes, sorted by Start and not overlapping, to
+// content.
+func applyChanges(content string, changes []planChange) string {
+	var b strings.Builder
+	last := 0
+	for _, change := range changes {
+		b.WriteString(content[last:change.Start])
+		b.WriteString(change.New)
+		last = change.End
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
+
+func planPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// plannedRenames lists the renames processFiles would perform, in the same
+// order: each folder's files first, then its subfolders, then the folder.
+func plannedRenames(dir string) ([]pathRename, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var renames []pathRename
+	for _, oldName := range sortedRenameNames() {
+		newName := globalRenames[oldName]
+		fileName := componentFileName(dir, oldName, newName)
+		if oldName == fileName || opts.dirsOnly {
+			continue
+		}
+		renamed := false
+		for _, ext := range componentExtensions {
+			oldPath := filepath.Join(dir, oldName+ext)
+			if _, err := os.Stat(oldPath); err != nil || !inFileFilter(oldPath) {
+				continue
+			}
+			renames = append(renames, pathRename{From: oldPath, To: filepath.Join(dir, fileName+ext)})
+			renamed = true
+		}
+		if !renamed || oldName == newName {
+			continue
+		}
+		for _, suffix := range siblingSuffixes {
+			sibling := filepath.Join(dir, oldName+suffix)
+			if _, err := os.Stat(sibling); err == nil {
+				renames = append(renames, pathRename{From: sibling, To: filepath.Join(dir, newName+suffix)})
+			}
+		}
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		subdir := filepath.Join(dir, entry.Name())
+		nested, err := plannedRenames(subdir)
+		if err != nil {
+			return nil, err
+		}
+		renames = append(renames, nested...)
+		if newName, ok := globalRenames[entry.Name()]; ok && newName != entry.Name() && fileFilter == nil {
+			renames = append(renames, pathRename{From: subdir, To: filepath.Join(dir, newName)})
+		}
+	}
+	return renames, nil
+}
+
+func loadPlan(path string) (*RenamePlan, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading plan: %v", err)
+	}
+	var r report
+	if err := json.Unmarshal(content, &r); err != nil {
+		return nil, fmt.Errorf("malformed plan %s: %v", path, err)
+	}
+	if r.Plan == nil {
+		return nil, fmt.Errorf("%s has no plan; generate it with --dry-run --report-file", path)
+	}
+	return r.Plan, nil
+}
+
+// validate checks that the tree is still as it was when the plan was made:
+// every scanned file has the content that was hashed, each edit's changes
+// fit that content, and every rename source is present.
+func (p *RenamePlan) validate() error {
+	sources := make([]string, 0, len(p.Sources))
+	for source := range p.Sources {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		content, err := os.ReadFile(filepath.Join(p.Root, filepath.FromSlash(source)))
+		if err != nil {
+			return fmt.Errorf("%s: %v", source, err)
+		}
+		if hashContent(content) != p.Sources[source] {
+			return fmt.Errorf("%s has changed since the plan was made", source)
+		}
+	}
+	for _, edit := range p.Edits {
+		path := filepath.Join(p.Root, filepath.FromSlash(edit.Path))
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("%s: %v", edit.Path, err)
+		}
+		if hashContent(content) != edit.SHA256 {
+			return fmt.Errorf("%s has changed since the plan was made", edit.Path)
+		}
+		if err := checkChanges(string(content), edit.Changes); err != nil {
+			return fmt.Errorf("%s: %v", edit.Path, err)
+		}
+	}
+	for _, rename := range p.Renames {
+		if _, err := os.Stat(filepath.Join(p.Root, filepath.FromSlash(rename.From))); err != nil {
+			return fmt.Errorf("%s: %v", rename.From, err)
+		}
+	}
+	return nil
+}
+
+// checkChanges rejects hand-edited changes that applyChanges cannot apply:
+// ranges out of order, overlapping, outside content or not covering Old.
+func checkChanges(content string, changes []planChange) error {
+	last := 0
+	for _, change := range changes {
+		if change.Start < last || change.End < change.Start || change.End > len(content) {
+			return fmt.Errorf("change at %d-%d is out of order, overlapping or outside the file", change.Start, change.End)
+		}
+		if content[change.Start:change.End] != change.Old {
+			return fmt.Errorf("change at %d-%d does not match %q", change.Start, change.End, change.Old)
+		}
+		last = change.End
+	}
+	return nil
+}
+
+// Apply checks that the tree still matches the plan and then carries it out.
+func (p *RenamePlan) Apply() error {
+	if err := p.validate(); err != nil {
+		return fmt.Errorf("plan is out of date: %v", err)
+	}
+	return p.apply()
+}
+
+// apply carries out a validated plan. An edit with no changes left in it,
+// as a tool applying part of the plan leaves it, writes nothing.
+func (p *RenamePlan) apply() error {
+	for _, edit := range p.Edits {
+		if len(edit.Changes) == 0 {
+			continue
+		}
+		path := filepath.Join(p.Root, filepath.FromSlash(edit.Path))
+		original, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		content := applyChanges(string(original), edit.Changes)
+		if content == string(original) {
+			continue
+		}
+		if err := writeFileRetrying(path, []byte(content), 0644); err != nil {
+			return err
+		}
+		recordChange(path)
+		fmt.Fprintf(stdout, "Updated imports in: %s\n", edit.Path)
+	}
+	for _, rename := range p.Renames {
+		from := filepath.Join(p.Root, filepath.FromSlash(rename.From))
+		to := filepath.Join(p.Root, filepath.FromSlash(rename.To))
+		if err := renamePath(from, to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runApply implements `rename-shadcn-vue apply --plan plan.json`.
+func runApply(args []string) int {
+	fs := flag.NewFlagSet("rename-shadcn-vue apply", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	planFile := fs.String("plan", "", "plan written by --dry-run --report-file")
+	fs.BoolVar(&opts.useGitMv, "use-git-mv", false, "rename files with git mv so history is preserved, falling back to a plain rename")
+	allowConflicts := fs.Bool("allow-conflicts", false, "apply the plan even though it lists conflicts")
+	fs.IntVar(&opts.retries, "retries", 3, "retry a write or rename this many times, with backoff, when it fails with a transient error such as EBUSY")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if *planFile == "" {
+		fmt.Fprintln(stdout, "Error: apply requires --plan")
+		return 2
+	}
+
+	p, err := loadPlan(*planFile)
+	if err != nil {
+		fmt.Fprintf(stdout, "Error: %v\n", err)
+		return 1
+	}
+	pathBase = p.Root
+	if len(p.Conflicts) > 0 && !*allowConflicts {
+		fmt.Fprintln(stdout, "Error: the plan lists conflicts:")
+		for _, conflict := range p.Conflicts {
+			fmt.Fprintf(stdout, "  %s\n", conflict)
+		}
+		fmt.Fprintln(stdout, "Resolve them and make a new plan, or re-run with --allow-conflicts.")
+		return 1
+	}
+	if err := p.validate(); err != nil {
+		fmt.Fprintf(stdout, "Error: plan is out of date: %v\n", err)
+		fmt.Fprintln(stdout, "Re-run with --dry-run --report-file to make a new plan.")
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "Applying plan from %s: %d edit(s), %d rename(s)\n", *planFile, len(p.Edits), len(p.Renames))
+	if err := p.apply(); err != nil {
+		fmt.Fprintf(stdout, "Error applying plan: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(stdout, "\nAll changes completed successfully!")
+	return 0
+}
@@ -0,0 +1,390 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writePlanFixture(t *testing.T, dir string) {
+	files := map[string]string{
+		"Dialog/Dialog.vue":        `export default {}`,
+		"Dialog/DialogContent.vue": `import Dialog from './Dialog.vue'`,
+		"Dialog/index.ts":          `export { default as Dialog } from './Dialog.vue'`,
+		"App.vue":                  `import { Dialog } from '@/components/ui/Dialog'`,
+	}
+	writeFiles(t, dir, files)
+}
+
+func TestApplyPlan(t *testing.T) {
+	globalRenames = map[string]string{"Dialog": "dialog", "DialogContent": "dialog-content"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	tmpDir := t.TempDir()
+	root := filepath.Join(tmpDir, "ui")
+	writePlanFixture(t, root)
+
+	p, err := buildPlan(root)
+	if err != nil {
+		t.Fatalf("buildPlan failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "Dialog", "Dialog.vue")); err != nil {
+		t.Fatalf("buildPlan touched disk: %v", err)
+	}
+
+	planFile := filepath.Join(tmpDir, "plan.json")
+	r := buildReport(root, 0, 0, 0)
+	r.Plan = p
+	if err := writeReport(planFile, r); err != nil {
+		t.Fatalf("writeReport failed: %v", err)
+	}
+
+	// Applying must not depend on the rename map; the plan carries everything.
+	globalRenames = make(map[string]string)
+	loaded, err := loadPlan(planFile)
+	if err != nil {
+		t.Fatalf("loadPlan failed: %v", err)
+	}
+	if err := loaded.validate(); err != nil {
+		t.Fatalf("validate failed: %v", err)
+	}
+	if err := loaded.apply(); err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"dialog/dialog.vue":         `export default {}`,
+		"dialog/dialog-content.vue": `import Dialog from './dialog.vue'`,
+		"dialog/index.ts":           `export { default as Dialog } from './dialog.vue'`,
+		"App.vue":                   `import { Dialog } from '@/components/ui/dialog'`,
+	}
+	for path, want := range expected {
+		content, err := os.ReadFile(filepath.Join(root, path))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", path, want, content)
+		}
+	}
+}
+
+func TestApplyPlanRejectsChangedFiles(t *testing.T) {
+	globalRenames = map[string]string{"Dialog": "dialog"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	tmpDir := t.TempDir()
+	writePlanFixture(t, tmpDir)
+
+	p, err := buildPlan(tmpDir)
+	if err != nil {
+		t.Fatalf("buildPlan failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "App.vue"), []byte(`import { Dialog } from '@/components/ui/Dialog'
+import { Card } from '@/components/ui/Card'`), 0644); err != nil {
+		t.Fatalf("Failed to modify App.vue: %v", err)
+	}
+
+	if err := p.validate(); err == nil || !strings.Contains(err.Error(), "App.vue has changed") {
+		t.Errorf("validate() = %v; want App.vue changed error", err)
+	}
+}
+
+func TestApplyPlanRejectsChangedUneditedFiles(t *testing.T) {
+	globalRenames = map[string]string{"Dialog": "dialog"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	tmpDir := t.TempDir()
+	writePlanFixture(t, tmpDir)
+
+	p, err := buildPlan(tmpDir)
+	if err != nil {
+		t.Fatalf("buildPlan failed: %v", err)
+	}
+	if slices.ContainsFunc(p.Edits, func(e planEdit) bool { return e.Path == "Dialog/Dialog.vue" }) {
+		t.Fatalf("fixture expects Dialog/Dialog.vue to be left unedited")
+	}
+	// A file the plan leaves alone gains an import of a path the plan renames.
+	if err := os.WriteFile(filepath.Join(tmpDir, "Dialog", "Dialog.vue"), []byte(`import DialogContent from './DialogContent.vue'
+export default {}`), 0644); err != nil {
+		t.Fatalf("Failed to modify Dialog.vue: %v", err)
+	}
+
+	if err := p.validate(); err == nil || !strings.Contains(err.Error(), "Dialog/Dialog.vue has changed") {
+		t.Errorf("validate() = %v; want Dialog/Dialog.vue changed error", err)
+	}
+}
+
+func TestApplyPlanRejectsBadChanges(t *testing.T) {
+	globalRenames = map[string]string{"Dialog": "dialog"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	tmpDir := t.TempDir()
+	writePlanFixture(t, tmpDir)
+
+	tests := []struct {
+		name   string
+		change func(changes []planChange) []planChange
+	}{
+		{"past end of file", func(changes []planChange) []planChange {
+			changes[0].End = 1000
+			return changes
+		}},
+		{"reversed range", func(changes []planChange) []planChange {
+			changes[0].Start, changes[0].End = changes[0].End, changes[0].Start
+			return changes
+		}},
+		{"out of order", func(changes []planChange) []planChange {
+			return append(changes, planChange{Start: 0, End: 1, Old: "i", New: "I"})
+		}},
+		{"old text moved", func(changes []planChange) []planChange {
+			changes[0].Start--
+			changes[0].End--
+			return changes
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := buildPlan(tmpDir)
+			if err != nil {
+				t.Fatalf("buildPlan failed: %v", err)
+			}
+			i := slices.IndexFunc(p.Edits, func(e planEdit) bool { return e.Path == "App.vue" })
+			if i < 0 || len(p.Edits[i].Changes) == 0 {
+				t.Fatalf("plan has no changes for App.vue: %+v", p.Edits)
+			}
+			p.Edits[i].Changes = tt.change(p.Edits[i].Changes)
+
+			if err := p.validate(); err == nil || !strings.Contains(err.Error(), "App.vue: change at") {
+				t.Errorf("validate() = %v; want a bad change error for App.vue", err)
+			}
+		})
+	}
+}
+
+func TestContentChanges(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		rewritten string
+		want      []string // Old -> New of each change
+	}{
+		{
+			name:      "one segment",
+			content:   "import Button from './Button.vue'\n",
+			rewritten: "import Button from './button.vue'\n",
+			want:      []string{"Button -> button"},
+		},
+		{
+			name:      "several imports on one line",
+			content:   "import { Dialog } from '@/components/ui/Dialog'; import Card from '../Card/Card.vue'\n",
+			rewritten: "import { Dialog } from '@/components/ui/dialog'; import Card from '../card/card.vue'\n",
+			want:      []string{"Dialog -> dialog", "Card -> card", "Card -> card"},
+		},
+		{
+			name:      "requoted specifier",
+			content:   "const a = 1\nimport Button from \"./Button.vue\"\r\n",
+			rewritten: "const a = 1\nimport Button from './button.vue'\r\n",
+			want:      []string{` "./Button ->  './button`, "\"\r\n -> '\r\n"},
+		},
+		{
+			name:      "line count changed",
+			content:   "import Button from './Button.vue'\n",
+			rewritten: "import Button from './button.vue'\n\n",
+			want:      []string{"Button.vue' -> button.vue'\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := contentChanges(tt.content, tt.rewritten)
+			var got []string
+			for _, change := range changes {
+				got = append(got, change.Old+" -> "+change.New)
+				if change.End-change.Start != change.Length || tt.content[change.Start:change.Start+change.Length] != change.Old {
+					t.Errorf("change %+v does not cover %q in the content", change, change.Old)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("contentChanges() = %q; want %q", got, tt.want)
+			}
+			if applied := applyChanges(tt.content, changes); applied != tt.rewritten {
+				t.Errorf("applyChanges() = %q; want %q", applied, tt.rewritten)
+			}
+		})
+	}
+}
+
+func TestPlanSelectiveApply(t *testing.T) {
+	defer func() {
+		opts = options{}
+		componentIndex = nil
+		globalRenames = make(map[string]string)
+	}()
+
+	tmpDir := t.TempDir()
+	writePlanFixture(t, tmpDir)
+	app := "import { Dialog } from '@/components/ui/Dialog'\nimport DialogContent from './Dialog/DialogContent.vue'\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "App.vue"), []byte(app), 0644); err != nil {
+		t.Fatalf("Failed to write App.vue: %v", err)
+	}
+
+	p, err := Plan(tmpDir, options{})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "Dialog", "Dialog.vue")); err != nil {
+		t.Fatalf("Plan touched disk: %v", err)
+	}
+	if len(p.Conflicts) != 0 {
+		t.Errorf("Conflicts = %v; want none", p.Conflicts)
+	}
+
+	var edit *planEdit
+	for i := range p.Edits {
+		if p.Edits[i].Path == "App.vue" {
+			edit = &p.Edits[i]
+		}
+	}
+	if edit == nil {
+		t.Fatalf("no edit planned for App.vue: %+v", p.Edits)
+	}
+	wantChanges := []planChange{
+		{Start: 40, End: 46, Length: 6, Old: "Dialog", New: "dialog"},
+		{Start: 77, End: 83, Length: 6, Old: "Dialog", New: "dialog"},
+		{Start: 84, End: 97, Length: 13, Old: "DialogContent", New: "dialog-content"},
+	}
+	if len(edit.Changes) != len(wantChanges) {
+		t.Fatalf("Changes = %+v; want %+v", edit.Changes, wantChanges)
+	}
+	for i, change := range edit.Changes {
+		if app[change.Start:change.Start+change.Length] != change.Old {
+			t.Errorf("change %d covers %q; want %q", i, app[change.Start:change.Start+change.Length], change.Old)
+		}
+		if change != wantChanges[i] {
+			t.Errorf("change %d = %+v; want %+v", i, change, wantChanges[i])
+		}
+	}
+
+	// Keep only the first change in App.vue and apply nothing else.
+	edit.Changes = edit.Changes[:1]
+	p.Edits = []planEdit{*edit}
+	p.Renames = nil
+	if err := p.Apply(); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "App.vue"))
+	if err != nil {
+		t.Fatalf("Failed to read App.vue: %v", err)
+	}
+	want := "import { Dialog } from '@/components/ui/dialog'\nimport DialogContent from './Dialog/DialogContent.vue'\n"
+	if string(content) != want {
+		t.Errorf("App.vue:\nExpected:\n%s\nGot:\n%s", want, content)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "Dialog", "Dialog.vue")); err != nil {
+		t.Errorf("dropped rename was applied: %v", err)
+	}
+}
+
+func TestPlanIgnoresEarlierRunState(t *testing.T) {
+	defer func() {
+		resetRunState(options{})
+		globalRenames = make(map[string]string)
+	}()
+
+	tmpDir := t.TempDir()
+	writePlanFixture(t, tmpDir)
+
+	// State an earlier --scope --since run in this process would leave.
+	scopeRoot = filepath.Join(tmpDir, "Dialog")
+	fileFilter = map[string]bool{}
+	componentIndex = map[string]string{}
+
+	p, err := Plan(tmpDir, options{})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if !slices.ContainsFunc(p.Edits, func(e planEdit) bool { return e.Path == "App.vue" }) {
+		t.Errorf("Edits = %+v; want App.vue rewritten", p.Edits)
+	}
+	if !slices.Contains(p.Renames, pathRename{From: "Dialog", To: "dialog"}) {
+		t.Errorf("Renames = %+v; want Dialog -> dialog", p.Renames)
+	}
+}
+
+func TestApplyPlanSkipsEditsWithoutChanges(t *testing.T) {
+	globalRenames = map[string]string{"Dialog": "dialog"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	tmpDir := t.TempDir()
+	writePlanFixture(t, tmpDir)
+
+	p, err := buildPlan(tmpDir)
+	if err != nil {
+		t.Fatalf("buildPlan failed: %v", err)
+	}
+	for i := range p.Edits {
+		p.Edits[i].Changes = nil
+	}
+	p.Renames = nil
+	if err := p.Apply(); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(tmpDir, "App.vue"))
+	if err != nil {
+		t.Fatalf("Failed to read App.vue: %v", err)
+	}
+	if want := `import { Dialog } from '@/components/ui/Dialog'`; string(content) != want {
+		t.Errorf("edit without changes was written:\n%s", content)
+	}
+}
+
+func TestPlanConflicts(t *testing.T) {
+	defer func() {
+		opts = options{}
+		pathBase = ""
+		componentIndex = nil
+		globalRenames = make(map[string]string)
+	}()
+
+	tmpDir := t.TempDir()
+	writePlanFixture(t, tmpDir)
+	taken := filepath.Join(tmpDir, "Dialog", "dialog.vue")
+	if err := os.WriteFile(taken, []byte(`export default {}`), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", taken, err)
+	}
+	if info, err := os.Stat(filepath.Join(tmpDir, "Dialog", "Dialog.vue")); err == nil {
+		if takenInfo, err := os.Stat(taken); err == nil && os.SameFile(info, takenInfo) {
+			t.Skip("case-insensitive file system")
+		}
+	}
+
+	p, err := Plan(tmpDir, options{})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	want := "Dialog/Dialog.vue: Dialog/dialog.vue already exists"
+	if len(p.Conflicts) != 1 || p.Conflicts[0] != want {
+		t.Errorf("Conflicts = %v; want [%s]", p.Conflicts, want)
+	}
+
+	previous := stdout
+	stdout = io.Discard
+	defer func() { stdout = previous }()
+	planFile := filepath.Join(t.TempDir(), "plan.json")
+	r := buildReport(tmpDir, 0, 0, 0)
+	r.Plan = p
+	if err := writeReport(planFile, r); err != nil {
+		t.Fatalf("writeReport failed: %v", err)
+	}
+	if code := runApply([]string{"--plan", planFile}); code != 1 {
+		t.Errorf("apply with conflicts = %d; want 1", code)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "Dialog", "Dialog.vue")); err != nil {
+		t.Errorf("apply with conflicts touched disk: %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// With --silent-if-clean, output is held back until the run is known to
+// change something. A clean tree then exits without printing anything.
+var (
+	quietBuffer *bytes.Buffer
+	quietTarget io.Writer
+)
+
+func holdOutput() {
+	quietTarget = stdout
+	quietBuffer = &bytes.Buffer{}
+	stdout = quietBuffer
+}
+
+func releaseOutput() {
+	if quietBuffer == nil {
+		return
+	}
+	stdout = quietTarget
+	stdout.Write(quietBuffer.Bytes())
+	quietBuffer = nil
+}
+
+func discardOutput() {
+	if quietBuffer == nil {
+		return
+	}
+	stdout = quietTarget
+	quietBuffer = nil
+}
+
+// exit releases held output before a failing exit so errors are never
+// swallowed by --silent-if-clean.
+func exit(code int) {
+	cleanup()
+	if code != 0 {
+		releaseOutput()
+	}
+	os.Exit(code)
+}
+
+// cleanup flushes the trace and releases the lock before the process exits.
+func cleanup() {
+	closeTrace()
+	releaseLock()
+}
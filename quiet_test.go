@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSilentIfClean(t *testing.T) {
+	var output bytes.Buffer
+	original := stdout
+	stdout = &output
+	defer func() { stdout = original }()
+
+	tmpDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "card.vue"), []byte(`import { cn } from '@/lib/utils'`), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	globalRenames = make(map[string]string)
+	holdOutput()
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	fmt.Fprintln(stdout, "No PascalCase imports found to rename.")
+	if len(globalRenames) != 0 {
+		t.Fatalf("globalRenames = %v; want empty for a clean tree", globalRenames)
+	}
+	discardOutput()
+
+	if output.Len() != 0 {
+		t.Errorf("clean tree printed %q; want no output", output.String())
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "card.vue"), []byte(`import Button from './Button.vue'`), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	holdOutput()
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	releaseOutput()
+	globalRenames = make(map[string]string)
+
+	if !strings.Contains(output.String(), "Found PascalCase import to rename: Button -> button") {
+		t.Errorf("held output was not released once a change was found; got %q", output.String())
+	}
+}
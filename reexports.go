@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var reexportRegex = regexp.MustCompile(`export\s*(?:\*(?:\s*as\s+[A-Za-z_$][\w$]*)?|{[^}]*})\s*from\s*['"](\.\.?/[^'"]+)['"]`)
+
+// followReexports is --follow-reexports: starting at the root barrel, every
+// re-export chain is followed down to the .vue files it ends in. Those files
+// and the PascalCase folders the chain passes through are renamed even when
+// their names do not start with a known component prefix, so every specifier
+// that reaches a component through the barrels is covered.
+func followReexports(dir string) error {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	visited := make(map[string]bool)
+	for _, barrel := range barrelFileNames {
+		path := filepath.Join(root, barrel)
+		if _, err := os.Stat(path); err == nil {
+			if err := followBarrel(root, path, visited); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func followBarrel(root, barrelPath string, visited map[string]bool) error {
+	if visited[barrelPath] {
+		return nil
+	}
+	visited[barrelPath] = true
+
+	content, err := os.ReadFile(barrelPath)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", barrelPath, err)
+	}
+	spans := commentSpans(string(content))
+	for _, match := range reexportRegex.FindAllStringSubmatchIndex(string(content), -1) {
+		if inComment(spans, match[0]) {
+			continue
+		}
+		target, ok := resolveReexport(filepath.Dir(barrelPath), string(content[match[2]:match[3]]))
+		if !ok {
+			continue
+		}
+		rel, err := filepath.Rel(root, target)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		for _, segment := range strings.Split(filepath.Dir(rel), string(filepath.Separator)) {
+			addReexportedRename(segment, barrelPath)
+		}
+		if filepath.Ext(target) == ".vue" {
+			addReexportedRename(strings.TrimSuffix(filepath.Base(target), ".vue"), barrelPath)
+			continue
+		}
+		if err := followBarrel(root, target, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveReexport finds the file a re-export specifier points at: the file
+// itself, the file with a .vue or .ts extension, or a folder's barrel.
+func resolveReexport(dir, specifier string) (string, bool) {
+	path := filepath.Join(dir, filepath.FromSlash(specifier))
+	candidates := []string{path, path + ".vue", path + ".ts", path + ".js"}
+	for _, barrel := range barrelFileNames {
+		candidates = append(candidates, filepath.Join(path, barrel))
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+func addReexportedRename(name, barrelPath string) {
+	if name == "" || name == "." || !unicode.IsUpper(rune(name[0])) {
+		return
+	}
+	if newName, ok := addDetectedRename(name); ok {
+		fmt.Fprintf(stdout, "Found re-exported component to rename: %s -> %s via %s\n", name, newName, displayPath(barrelPath))
+	}
+}
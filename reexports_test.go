@@ -0,0 +1,69 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFollowReexports(t *testing.T) {
+	previous := stdout
+	stdout = io.Discard
+	defer func() { stdout = previous }()
+	defer func() { globalRenames = make(map[string]string) }()
+
+	tmpDir := t.TempDir()
+
+	// FancyCard has no known component prefix, so it is only found by
+	// following index.ts -> Widgets/index.ts -> Widgets/Gallery/index.ts.
+	files := map[string]string{
+		"index.ts":                       `export * from './Widgets'`,
+		"Widgets/index.ts":               `export * from './Gallery'`,
+		"Widgets/Gallery/index.ts":       `export { default as FancyCard } from './FancyCard.vue'`,
+		"Widgets/Gallery/FancyCard.vue":  `export default {}`,
+		"Widgets/Gallery/Unexported.vue": `export default {}`,
+	}
+	writeFiles(t, tmpDir, files)
+
+	globalRenames = make(map[string]string)
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if len(globalRenames) != 0 {
+		t.Fatalf("globalRenames = %v before following re-exports; want none", globalRenames)
+	}
+
+	if err := followReexports(tmpDir); err != nil {
+		t.Fatalf("followReexports failed: %v", err)
+	}
+	want := map[string]string{"Widgets": "widgets", "Gallery": "gallery", "FancyCard": "fancy-card"}
+	if len(globalRenames) != len(want) {
+		t.Errorf("globalRenames = %v; want %v", globalRenames, want)
+	}
+	for oldName, newName := range want {
+		if globalRenames[oldName] != newName {
+			t.Errorf("globalRenames[%s] = %q; want %q", oldName, globalRenames[oldName], newName)
+		}
+	}
+
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+	expected := map[string]string{
+		"index.ts":                       `export * from './widgets'`,
+		"widgets/index.ts":               `export * from './gallery'`,
+		"widgets/gallery/index.ts":       `export { default as FancyCard } from './fancy-card.vue'`,
+		"widgets/gallery/fancy-card.vue": `export default {}`,
+		"widgets/gallery/Unexported.vue": `export default {}`,
+	}
+	for path, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tmpDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", path, want, content)
+		}
+	}
+}
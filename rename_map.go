@@ -0,0 +1,29 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// loadRenameMap reads an old -> new JSON object, as written by
+// --rename-map-out, for reuse with --map.
+func loadRenameMap(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rename map: %v", err)
+	}
+	renames, err := decodeStringTable(content)
+	if err != nil {
+		return nil, fmt.Errorf("malformed rename map %s: %v", path, err)
+	}
+	return renames, nil
+}
+
+func writeRenameMap(path string, renames map[string]string) error {
+	content, err := json.MarshalIndent(renames, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
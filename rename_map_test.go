@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenameMapRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "App.vue"), []byte(`import { AlertDialog } from '@/components/ui/AlertDialog'
+import Button from './Button.vue'`), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	globalRenames = make(map[string]string)
+	defer func() { globalRenames = make(map[string]string) }()
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+
+	mapFile := filepath.Join(tmpDir, "renames.json")
+	if err := writeRenameMap(mapFile, globalRenames); err != nil {
+		t.Fatalf("writeRenameMap failed: %v", err)
+	}
+	loaded, err := loadRenameMap(mapFile)
+	if err != nil {
+		t.Fatalf("loadRenameMap failed: %v", err)
+	}
+
+	if len(loaded) != len(globalRenames) {
+		t.Fatalf("loaded map = %v; want %v", loaded, globalRenames)
+	}
+	for oldName, newName := range globalRenames {
+		if loaded[oldName] != newName {
+			t.Errorf("loaded[%q] = %q; want %q", oldName, loaded[oldName], newName)
+		}
+	}
+}
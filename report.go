@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type componentRename struct {
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+type report struct {
+	ComponentsDir  string            `json:"componentsDir"`
+	Components     []componentRename `json:"components"`
+	FilesRewritten []string          `json:"filesRewritten"`
+	PathsRenamed   []pathRename      `json:"pathsRenamed"`
+	Counts         reportCounts      `json:"counts"`
+	Timings        reportTimings     `json:"timings"`
+	Plan           *RenamePlan       `json:"plan,omitempty"`
+}
+
+type reportCounts struct {
+	Components     int `json:"components"`
+	FilesRewritten int `json:"filesRewritten"`
+	PathsRenamed   int `json:"pathsRenamed"`
+}
+
+type reportTimings struct {
+	ScanMs  int64 `json:"scanMs"`
+	ApplyMs int64 `json:"applyMs"`
+	TotalMs int64 `json:"totalMs"`
+}
+
+func buildReport(dir string, scan, apply, total time.Duration) report {
+	r := report{
+		ComponentsDir:  dir,
+		Components:     []componentRename{},
+		FilesRewritten: []string{},
+		PathsRenamed:   []pathRename{},
+		Timings: reportTimings{
+			ScanMs:  scan.Milliseconds(),
+			ApplyMs: apply.Milliseconds(),
+			TotalMs: total.Milliseconds(),
+		},
+	}
+	for _, path := range sortedPaths(rewrittenFiles) {
+		r.FilesRewritten = append(r.FilesRewritten, displayPath(path))
+	}
+	for _, rename := range renamedPaths {
+		r.PathsRenamed = append(r.PathsRenamed, pathRename{From: displayPath(rename.From), To: displayPath(rename.To)})
+	}
+	for _, name := range sortedRenameNames() {
+		r.Components = append(r.Components, componentRename{Old: name, New: globalRenames[name]})
+	}
+	r.Counts = reportCounts{
+		Components:     len(r.Components),
+		FilesRewritten: len(r.FilesRewritten),
+		PathsRenamed:   len(r.PathsRenamed),
+	}
+	return r
+}
+
+// pathBase is the directory reported paths are made relative to: the
+// components root, or --relative-to when given. Paths use forward slashes so
+// reports are stable across machines.
+var pathBase string
+
+func displayPath(path string) string {
+	if pathBase == "" {
+		return path
+	}
+	base, err := filepath.Abs(pathBase)
+	if err != nil {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(base, abs)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// printJSONReport is --json: the log held back since startup is dropped and
+// the report is printed in its place.
+func printJSONReport(r report) {
+	discardOutput()
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		fmt.Fprintf(stdout, "Error writing report: %v\n", err)
+		exit(1)
+	}
+	stdout.Write(append(content, '\n'))
+}
+
+func writeReport(path string, r report) error {
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteReport(t *testing.T) {
+	changedFiles = make(map[string]bool)
+	rewrittenFiles = make(map[string]bool)
+	renamedPaths = nil
+	globalRenames = map[string]string{"Button": "button"}
+	defer func() {
+		changedFiles = make(map[string]bool)
+		rewrittenFiles = make(map[string]bool)
+		renamedPaths = nil
+		globalRenames = make(map[string]string)
+	}()
+
+	tmpDir := t.TempDir()
+
+	buttonDir := filepath.Join(tmpDir, "Button")
+	if err := os.MkdirAll(buttonDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	for _, name := range []string{"Button.vue", "index.ts"} {
+		if err := os.WriteFile(filepath.Join(buttonDir, name), []byte(""), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	recordChange(filepath.Join(buttonDir, "index.ts"))
+	if err := renamePath(filepath.Join(buttonDir, "Button.vue"), filepath.Join(buttonDir, "button.vue")); err != nil {
+		t.Fatalf("renamePath failed: %v", err)
+	}
+	if err := renamePath(buttonDir, filepath.Join(tmpDir, "button")); err != nil {
+		t.Fatalf("renamePath failed: %v", err)
+	}
+
+	reportPath := filepath.Join(tmpDir, "report.json")
+	if err := writeReport(reportPath, buildReport(tmpDir, 0, 0, 0)); err != nil {
+		t.Fatalf("writeReport failed: %v", err)
+	}
+
+	content, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	if !json.Valid(content) {
+		t.Fatalf("report is not valid JSON:\n%s", content)
+	}
+
+	var r report
+	if err := json.Unmarshal(content, &r); err != nil {
+		t.Fatalf("Failed to decode report: %v", err)
+	}
+	if len(r.Components) != 1 || r.Components[0].Old != "Button" || r.Components[0].New != "button" {
+		t.Errorf("Components = %v; want [{Button button}]", r.Components)
+	}
+	if want := filepath.Join(tmpDir, "button", "index.ts"); len(r.FilesRewritten) != 1 || r.FilesRewritten[0] != want {
+		t.Errorf("FilesRewritten = %v; want [%s]", r.FilesRewritten, want)
+	}
+	if r.Counts.PathsRenamed != 2 {
+		t.Errorf("Counts.PathsRenamed = %d; want 2", r.Counts.PathsRenamed)
+	}
+}
+
+func TestReportPathsRelativeToRoot(t *testing.T) {
+	rewrittenFiles = make(map[string]bool)
+	renamedPaths = nil
+	defer func() {
+		rewrittenFiles = make(map[string]bool)
+		renamedPaths = nil
+		pathBase = ""
+	}()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	root := filepath.Join(cwd, "src", "components", "ui")
+
+	rewrittenFiles[filepath.Join(root, "button", "index.ts")] = true
+	renamedPaths = append(renamedPaths, pathRename{
+		From: filepath.Join("src", "components", "ui", "Button", "Button.vue"),
+		To:   filepath.Join("src", "components", "ui", "Button", "button.vue"),
+	})
+
+	pathBase = filepath.Join("src", "components", "ui")
+	r := buildReport(root, 0, 0, 0)
+	if len(r.FilesRewritten) != 1 || r.FilesRewritten[0] != "button/index.ts" {
+		t.Errorf("FilesRewritten = %v; want [button/index.ts]", r.FilesRewritten)
+	}
+	want := pathRename{From: "Button/Button.vue", To: "Button/button.vue"}
+	if len(r.PathsRenamed) != 1 || r.PathsRenamed[0] != want {
+		t.Errorf("PathsRenamed = %v; want [%v]", r.PathsRenamed, want)
+	}
+
+	pathBase = cwd
+	if got := displayPath(filepath.Join(root, "button", "index.ts")); got != "src/components/ui/button/index.ts" {
+		t.Errorf("displayPath with --relative-to = %q; want %q", got, "src/components/ui/button/index.ts")
+	}
+}
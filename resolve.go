@@ -0,0 +1,237 @@
+package main
+
+import (
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// componentIndex is the path-keyed rename map: every path under the
+// components root, as it is on disk when the run starts, mapped to where it
+// will live afterwards. Relative and @/components/ specifiers are resolved
+// against it so a component is only rewritten when the import actually
+// points at a file being renamed, not at a same-named file in a different
+// folder. A nil index falls back to name-based patterns.
+//
+// scopeRoot is the absolute --scope folder, if any. Only components under it
+// are renamed, so imports of components elsewhere are kept as written.
+var (
+	componentIndex map[string]string
+	componentRoot  string
+	scopeRoot      string
+)
+
+func indexComponents(root string) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+
+	if opts.collapseToIndex {
+		if collapsedDirs, err = findCollapsibleFolders(root); err != nil {
+			return err
+		}
+	}
+
+	index := make(map[string]string)
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// The root maps to itself so imports from outside it, e.g. from a
+		// --scope folder, can walk back in through it.
+		if path == root {
+			index[path] = path
+			return nil
+		}
+
+		planned := plannedPath(root, path)
+		if d.IsDir() {
+			if newName, ok := globalRenames[d.Name()]; ok && fileFilter == nil {
+				planned = filepath.Join(filepath.Dir(planned), newName)
+			}
+		}
+		index[path] = planned
+
+		// Extensionless keys let './Button' and './Dialog/index' resolve.
+		if ext := filepath.Ext(path); !d.IsDir() && (ext == ".vue" || isBarrelFile(d.Name())) {
+			bare := strings.TrimSuffix(path, ext)
+			if _, ok := index[bare]; !ok {
+				index[bare] = strings.TrimSuffix(planned, ext)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	componentIndex = index
+	componentRoot = root
+	return nil
+}
+
+// outsideScope reports whether specifier, imported from filePath, points at
+// a path under the components root but outside the --scope folder.
+func outsideScope(filePath, specifier string) bool {
+	if scopeRoot == "" || componentRoot == "" {
+		return false
+	}
+	target, ok := resolveSpecifier(componentRoot, filePath, specifier)
+	if !ok {
+		return false
+	}
+	target, err := filepath.Abs(target)
+	if err != nil {
+		return false
+	}
+	return isWithin(target, componentRoot) && !isWithin(target, scopeRoot)
+}
+
+func isRelativeSpecifier(specifier string) bool {
+	return specifier == "." || specifier == ".." ||
+		strings.HasPrefix(specifier, "./") || strings.HasPrefix(specifier, "../")
+}
+
+// resolveRelativeSpecifier walks a relative specifier segment by segment from
+// the importing file's directory and renames each segment that resolves to an
+// indexed path. Segments that leave the components root are kept as written.
+func resolveRelativeSpecifier(filePath, specifier string) string {
+	dir, err := filepath.Abs(filepath.Dir(filePath))
+	if err != nil {
+		return specifier
+	}
+	return resolveSegments(dir, specifier)
+}
+
+func resolveSegments(current, specifier string) string {
+	segments := strings.Split(specifier, "/")
+	for i, segment := range segments {
+		switch segment {
+		case "", ".":
+			continue
+		case "..":
+			current = filepath.Dir(current)
+			continue
+		}
+
+		found, newPath, ok := lookupComponentPath(current, segment)
+		if !ok {
+			return specifier
+		}
+		newSegment := filepath.Base(newPath)
+		if strings.HasSuffix(segment, ".vue") != strings.HasSuffix(newSegment, ".vue") {
+			newSegment = strings.TrimSuffix(newSegment, ".vue")
+		}
+		segments[i] = newSegment
+		current = found
+	}
+	return strings.Join(segments, "/")
+}
+
+// lookupComponentPath finds segment under dir in the index. It also accepts
+// the segment's kebab-case form, which is what is on disk once a resumed run
+// has already renamed it, and under --ignore-case-in-detection a differently
+// cased spelling of the component's name.
+func lookupComponentPath(dir, segment string) (string, string, bool) {
+	path := filepath.Join(dir, segment)
+	if newPath, ok := componentIndex[path]; ok {
+		return path, newPath, true
+	}
+
+	name := strings.TrimSuffix(segment, ".vue")
+	if newName, ok := globalRenames[name]; ok {
+		path = filepath.Join(dir, newName+strings.TrimPrefix(segment, name))
+		if newPath, ok := componentIndex[path]; ok {
+			return path, newPath, true
+		}
+	}
+	if oldName, ok := foldedRenameName(name); ok {
+		path = filepath.Join(dir, oldName+strings.TrimPrefix(segment, name))
+		if newPath, ok := componentIndex[path]; ok {
+			return path, newPath, true
+		}
+	}
+	return "", "", false
+}
+
+// rewriteFolderSegments is the --dirs-only rewrite: folder segments are
+// renamed and the file segment is kept. With an index, relative and ui-aliased
+// specifiers are resolved exactly. Without one, every segment but the last is
+// treated as a folder, and so is a last segment with no extension directly
+// under components/ui (a barrel import).
+func rewriteFolderSegments(filePath, specifier string) string {
+	prefix, rest := splitUISpecifier(specifier)
+
+	if componentIndex != nil {
+		if isRelativeSpecifier(specifier) {
+			return resolveRelativeSpecifier(filePath, specifier)
+		}
+		if rest != "" {
+			return prefix + resolveSegments(locateUIDir(componentRoot), rest)
+		}
+		return specifier
+	}
+
+	segments := strings.Split(specifier, "/")
+	for i, segment := range segments {
+		newName, ok := globalRenames[segment]
+		if !ok {
+			continue
+		}
+		barrel := rest != "" && i == len(segments)-1 && !strings.Contains(rest, "/")
+		if i < len(segments)-1 || barrel {
+			segments[i] = newName
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// componentsSpecifierRegex matches imports through the components folder
+// itself, such as @/components/forms/Button.vue, which the ui patterns do
+// not cover.
+var componentsSpecifierRegex = regexp.MustCompile(`^(` + aliasPattern + `components/)(.+)$`)
+
+// locateComponentsDir is the folder @/components/ points at for a run on
+// root: the parent of the ui folder, or root itself when it has none.
+func locateComponentsDir(root string) string {
+	ui := locateUIDir(root)
+	if ui == root && filepath.Base(root) != "ui" {
+		return root
+	}
+	return filepath.Dir(ui)
+}
+
+// resolveComponentsSpecifier resolves an @/components/ specifier outside ui
+// through the component index, so same-named components in different
+// folders each follow their own file. It reports false for ui specifiers,
+// which the name-based patterns handle, and for paths that are not indexed.
+func resolveComponentsSpecifier(specifier string) (string, bool) {
+	if componentRoot == "" || uiSpecifierRegex.MatchString(specifier) {
+		return specifier, false
+	}
+	match := componentsSpecifierRegex.FindStringSubmatch(specifier)
+	if match == nil {
+		return specifier, false
+	}
+	resolved := resolveSegments(locateComponentsDir(componentRoot), match[2])
+	if resolved == match[2] {
+		return specifier, false
+	}
+	return match[1] + resolved, true
+}
+
+// splitUISpecifier splits a ui-aliased specifier into its alias prefix and
+// the path under the components root. rest is empty for any other specifier.
+func splitUISpecifier(specifier string) (prefix, rest string) {
+	if match := uiSpecifierRegex.FindStringSubmatchIndex(specifier); match != nil {
+		return specifier[:match[2]], specifier[match[2]:]
+	}
+	for _, alias := range uiAliases {
+		if rest, ok := strings.CutPrefix(specifier, alias+"/"); ok {
+			return alias + "/", rest
+		}
+	}
+	return specifier, ""
+}
@@ -0,0 +1,403 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSameNameInDifferentFolders(t *testing.T) {
+	defer func() { componentIndex = nil }()
+
+	tmpDir := t.TempDir()
+
+	// Only components/ is processed; legacy/ sits outside the root and keeps
+	// its Button even though the name matches.
+	files := map[string]string{
+		"components/ui/Button.vue":    `export default {}`,
+		"components/forms/Button.vue": `export default {}`,
+		"legacy/Button/Button.vue":    `export default {}`,
+		"components/ui/Card.vue": `import Button from './Button.vue'
+import LegacyButton from '../../legacy/Button/Button.vue'`,
+		"components/forms/Form.vue": `import Button from './Button.vue'
+import UiButton from '../ui/Button.vue'`,
+	}
+	writeFiles(t, tmpDir, files)
+
+	root := filepath.Join(tmpDir, "components")
+	globalRenames = map[string]string{"Button": "button", "Card": "card", "Form": "form"}
+	defer func() { globalRenames = make(map[string]string) }()
+	if err := indexComponents(root); err != nil {
+		t.Fatalf("indexComponents failed: %v", err)
+	}
+	if err := processFiles(root); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"components/ui/card.vue": `import Button from './button.vue'
+import LegacyButton from '../../legacy/Button/Button.vue'`,
+		"components/forms/form.vue": `import Button from './button.vue'
+import UiButton from '../ui/button.vue'`,
+	}
+	for path, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tmpDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", path, want, content)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "legacy", "Button", "Button.vue")); err != nil {
+		t.Errorf("legacy Button.vue outside the root should not be renamed: %v", err)
+	}
+}
+
+// Same-named components in two folders under the root are each renamed in
+// place, and every import, relative or through @/components/, follows the
+// file it points at.
+func TestSameNameInsideRoot(t *testing.T) {
+	defer func() { componentIndex = nil }()
+
+	files := map[string]string{
+		"ui/Button.vue":    `export default {}`,
+		"forms/Button.vue": `export default {}`,
+		"App.vue": `import Button from '@/components/ui/Button.vue'
+import FormButton from '@/components/forms/Button.vue'`,
+		"forms/Form.vue": `import Button from './Button.vue'
+import UiButton from '../ui/Button.vue'`,
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = make(map[string]string)
+	defer func() { globalRenames = make(map[string]string) }()
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if err := indexComponents(tmpDir); err != nil {
+		t.Fatalf("indexComponents failed: %v", err)
+	}
+	unresolved, err := verifyPlan(tmpDir)
+	if err != nil {
+		t.Fatalf("verifyPlan failed: %v", err)
+	}
+	if len(unresolved) != 0 {
+		t.Errorf("verifyPlan = %v; want every import to resolve", unresolved)
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"ui/button.vue":    files["ui/Button.vue"],
+		"forms/button.vue": files["forms/Button.vue"],
+		"App.vue": `import Button from '@/components/ui/button.vue'
+import FormButton from '@/components/forms/button.vue'`,
+		"forms/Form.vue": `import Button from './button.vue'
+import UiButton from '../ui/button.vue'`,
+	}
+	for path, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tmpDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", path, want, content)
+		}
+	}
+}
+
+func TestDirsOnly(t *testing.T) {
+	opts = options{dirsOnly: true}
+	globalRenames = map[string]string{"Dialog": "dialog", "DialogContent": "dialog-content", "AlertDialog": "alert-dialog"}
+	defer func() {
+		opts = options{}
+		globalRenames = make(map[string]string)
+		componentIndex = nil
+	}()
+
+	input := `import { Dialog } from '@/components/ui/Dialog'
+import DialogContent from '@/components/ui/Dialog/DialogContent.vue'
+import { AlertDialog } from '../AlertDialog'`
+	expected := `import { Dialog } from '@/components/ui/dialog'
+import DialogContent from '@/components/ui/dialog/DialogContent.vue'
+import { AlertDialog } from '../AlertDialog'`
+	if result := rewriteImports("test.vue", input); result != expected {
+		t.Errorf("without index:\nExpected:\n%s\n\nGot:\n%s", expected, result)
+	}
+
+	files := map[string]string{
+		"Dialog/Dialog.vue":        `export default {}`,
+		"Dialog/DialogContent.vue": `import Dialog from './Dialog.vue'`,
+		"Dialog/index.ts":          `export { default as DialogContent } from './DialogContent.vue'`,
+		"AlertDialog/index.ts":     `export { Dialog } from '../Dialog'`,
+		"App.vue": `import { Dialog } from '@/components/ui/Dialog'
+import DialogContent from '@/components/ui/Dialog/DialogContent.vue'`,
+	}
+	tmpDir := writeTree(t, files)
+
+	if err := indexComponents(tmpDir); err != nil {
+		t.Fatalf("indexComponents failed: %v", err)
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	expectedFiles := map[string]string{
+		"dialog/Dialog.vue":        `export default {}`,
+		"dialog/DialogContent.vue": `import Dialog from './Dialog.vue'`,
+		"dialog/index.ts":          `export { default as DialogContent } from './DialogContent.vue'`,
+		"alert-dialog/index.ts":    `export { Dialog } from '../dialog'`,
+		"App.vue": `import { Dialog } from '@/components/ui/dialog'
+import DialogContent from '@/components/ui/dialog/DialogContent.vue'`,
+	}
+	for path, want := range expectedFiles {
+		content, err := os.ReadFile(filepath.Join(tmpDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", path, want, content)
+		}
+	}
+}
+
+// TestDirsOnlyAboveUI runs --dirs-only on the folder that holds ui/, so
+// @/components/ui imports must be resolved inside ui/.
+func TestDirsOnlyAboveUI(t *testing.T) {
+	opts = options{dirsOnly: true}
+	globalRenames = map[string]string{"Button": "button"}
+	defer func() {
+		opts = options{}
+		globalRenames = make(map[string]string)
+		componentIndex = nil
+	}()
+
+	files := map[string]string{
+		"ui/Button/Button.vue": `export default {}`,
+		"ui/Button/index.ts":   `export { default as Button } from './Button.vue'`,
+		"App.vue": `import { Button } from '@/components/ui/Button'
+import ButtonFile from '@/components/ui/Button/Button.vue'`,
+	}
+	tmpDir := writeTree(t, files)
+
+	if err := indexComponents(tmpDir); err != nil {
+		t.Fatalf("indexComponents failed: %v", err)
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	expected := `import { Button } from '@/components/ui/button'
+import ButtonFile from '@/components/ui/button/Button.vue'`
+	content, err := os.ReadFile(filepath.Join(tmpDir, "App.vue"))
+	if err != nil {
+		t.Fatalf("Failed to read App.vue: %v", err)
+	}
+	if string(content) != expected {
+		t.Errorf("\nExpected:\n%s\n\nGot:\n%s", expected, content)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "ui", "button", "Button.vue")); err != nil {
+		t.Errorf("ui/Button was not renamed: %v", err)
+	}
+}
+
+func TestAggregateBarrelChain(t *testing.T) {
+	for _, useIndex := range []bool{false, true} {
+		t.Run(fmt.Sprintf("index=%v", useIndex), func(t *testing.T) {
+			defer func() {
+				componentIndex = nil
+				globalRenames = make(map[string]string)
+			}()
+
+			files := map[string]string{
+				"index.ts": `export * from './Dialog'
+export * from "./AlertDialog/index"`,
+				"Dialog/index.ts": `export { default as Dialog } from './Dialog.vue'
+export { default as DialogContent } from './DialogContent.vue'`,
+				"Dialog/Dialog.vue":           `export default {}`,
+				"Dialog/DialogContent.vue":    `export default {}`,
+				"AlertDialog/index.ts":        `export { default as AlertDialog } from './AlertDialog.vue'`,
+				"AlertDialog/AlertDialog.vue": `export default {}`,
+			}
+			tmpDir := writeTree(t, files)
+
+			globalRenames = make(map[string]string)
+			if err := buildRenameMap(tmpDir); err != nil {
+				t.Fatalf("buildRenameMap failed: %v", err)
+			}
+			addRename("Dialog", "dialog")
+			addRename("AlertDialog", "alert-dialog")
+			if useIndex {
+				if err := indexComponents(tmpDir); err != nil {
+					t.Fatalf("indexComponents failed: %v", err)
+				}
+			}
+			if err := processFiles(tmpDir); err != nil {
+				t.Fatalf("processFiles failed: %v", err)
+			}
+
+			expected := map[string]string{
+				"index.ts": `export * from './dialog'
+export * from "./alert-dialog/index"`,
+				"dialog/index.ts": `export { default as Dialog } from './dialog.vue'
+export { default as DialogContent } from './dialog-content.vue'`,
+				"alert-dialog/index.ts": `export { default as AlertDialog } from './alert-dialog.vue'`,
+			}
+			for path, want := range expected {
+				content, err := os.ReadFile(filepath.Join(tmpDir, path))
+				if err != nil {
+					t.Fatalf("Failed to read %s: %v", path, err)
+				}
+				if string(content) != want {
+					t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", path, want, content)
+				}
+			}
+		})
+	}
+}
+
+func TestBarrelOnlyFolder(t *testing.T) {
+	files := map[string]string{
+		"Sheet/index.ts":     `export * from 'reka-ui'`,
+		"Utilities/index.ts": `export const noop = () => {}`,
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = make(map[string]string)
+	defer func() { globalRenames = make(map[string]string) }()
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if globalRenames["Sheet"] != "sheet" {
+		t.Fatalf("globalRenames = %v; want Sheet -> sheet", globalRenames)
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	for _, path := range []string{"sheet/index.ts", "Utilities/index.ts"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, path)); err != nil {
+			t.Errorf("expected %s to exist: %v", path, err)
+		}
+	}
+}
+
+func TestBarrelExportsDivergentFileName(t *testing.T) {
+	files := map[string]string{
+		"Accordion/Accordion.vue": `export default {}`,
+		"Accordion/Item.vue":      `export default {}`,
+		"Accordion/index.ts": `export { default as Accordion } from './Accordion.vue'
+export { default as AccordionItem } from './Item.vue'`,
+		"Home.vue": `import { Accordion, AccordionItem } from '@/components/ui/Accordion'
+import Item from '@/components/ui/Accordion/Item.vue'`,
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = make(map[string]string)
+	defer func() { globalRenames = make(map[string]string) }()
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if globalRenames["Item"] != "item" {
+		t.Fatalf("globalRenames = %v; want Item -> item", globalRenames)
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"accordion/index.ts": `export { default as Accordion } from './accordion.vue'
+export { default as AccordionItem } from './item.vue'`,
+		"Home.vue": `import { Accordion, AccordionItem } from '@/components/ui/accordion'
+import Item from '@/components/ui/accordion/item.vue'`,
+	}
+	for path, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tmpDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", path, want, content)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "accordion", "item.vue")); err != nil {
+		t.Errorf("expected accordion/item.vue to exist: %v", err)
+	}
+}
+
+// TestScopedRewrite builds the map from ui/ and, as --scope does, applies
+// changes under pages/ only.
+func TestScopedRewrite(t *testing.T) {
+	defer func() {
+		componentIndex = nil
+		scopeRoot = ""
+		globalRenames = make(map[string]string)
+	}()
+
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"ui/Button/index.ts":   `export { default as Button } from './Button.vue'`,
+		"ui/Button/Button.vue": `export default {}`,
+		"ui/Dialog/Dialog.vue": `export default {}`,
+		"ui/Dialog/DialogContent.vue": `import Dialog from './Dialog.vue'
+import Button from '../Button/Button.vue'
+import { Button as B } from '@/components/ui/Button'`,
+		"ui/Dialog/index.ts": `export { default as Dialog } from './Dialog.vue'`,
+		"ui/Card.vue":        `import Dialog from './Dialog/Dialog.vue'`,
+	}
+	writeFiles(t, tmpDir, files)
+
+	ui := filepath.Join(tmpDir, "ui")
+	scope := filepath.Join(ui, "Dialog")
+	globalRenames = make(map[string]string)
+	if err := buildRenameMap(ui); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+	if globalRenames["Button"] != "button" || globalRenames["Dialog"] != "dialog" {
+		t.Fatalf("globalRenames = %v; want Button and Dialog", globalRenames)
+	}
+	if err := indexComponents(ui); err != nil {
+		t.Fatalf("indexComponents failed: %v", err)
+	}
+	scopeRoot = scope
+
+	// Card.vue is outside the scope, so its import of the renamed Dialog.vue
+	// is left behind; verify must say so.
+	unresolved, err := verifyPlan(scope)
+	if err != nil {
+		t.Fatalf("verifyPlan failed: %v", err)
+	}
+	if len(unresolved) != 1 || unresolved[0].file != filepath.Join(ui, "Card.vue") {
+		t.Errorf("verifyPlan = %v; want only ui/Card.vue unresolved", unresolved)
+	}
+
+	if err := processFiles(scope); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	// Imports of Button, which is outside the scope and not renamed, are kept.
+	expected := map[string]string{
+		"ui/Dialog/DialogContent.vue": `import Dialog from './dialog.vue'
+import Button from '../Button/Button.vue'
+import { Button as B } from '@/components/ui/Button'`,
+		"ui/Dialog/index.ts":   `export { default as Dialog } from './dialog.vue'`,
+		"ui/Dialog/dialog.vue": files["ui/Dialog/Dialog.vue"],
+		"ui/Button/Button.vue": files["ui/Button/Button.vue"],
+		"ui/Button/index.ts":   files["ui/Button/index.ts"],
+		"ui/Card.vue":          files["ui/Card.vue"],
+	}
+	for path, want := range expected {
+		content, err := os.ReadFile(filepath.Join(tmpDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s:\nExpected:\n%s\n\nGot:\n%s", path, want, content)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"syscall"
+	"time"
+)
+
+// fileSystem is the seam the tree's reads, writes and renames go through, so
+// tests can stand in a file system that fails or is slow.
+type fileSystem interface {
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	Rename(oldPath, newPath string) error
+}
+
+type osFileSystem struct{}
+
+func (osFileSystem) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (osFileSystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+func (osFileSystem) Rename(oldPath, newPath string) error {
+	return os.Rename(oldPath, newPath)
+}
+
+var (
+	fileOps fileSystem = osFileSystem{}
+
+	// retryDelay is the wait before the first retry; it doubles each time.
+	retryDelay = 50 * time.Millisecond
+)
+
+// isTransient reports whether err is one that networked and virtualized
+// file systems, such as some Docker mounts, return for a moment and then
+// stop returning.
+func isTransient(err error) bool {
+	return errors.Is(err, syscall.EBUSY) || errors.Is(err, syscall.EAGAIN)
+}
+
+// withRetries runs op on path, retrying it up to --retries times with
+// backoff while it fails with a transient error.
+func withRetries(path, what string, op func() error) error {
+	delay := retryDelay
+	err := op()
+	for attempt := 1; attempt <= opts.retries && err != nil && isTransient(err); attempt++ {
+		logf(path, "Retrying %s of %s in %v (attempt %d of %d): %v\n", what, displayPath(path), delay, attempt, opts.retries, err)
+		time.Sleep(delay)
+		delay *= 2
+		err = op()
+	}
+	return err
+}
+
+func writeFileRetrying(name string, data []byte, perm fs.FileMode) error {
+	return withRetries(name, "write", func() error {
+		return withIOSlot(func() error {
+			return fileOps.WriteFile(name, data, perm)
+		})
+	})
+}
+
+func renameRetrying(oldPath, newPath string) error {
+	return withRetries(oldPath, "rename", func() error {
+		return withIOSlot(func() error {
+			return fileOps.Rename(oldPath, newPath)
+		})
+	})
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// flakyFileSystem fails the first failures calls with err, then passes
+// calls through to the real file system.
+type flakyFileSystem struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyFileSystem) fail() error {
+	f.calls++
+	if f.calls <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+func (f *flakyFileSystem) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (f *flakyFileSystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if err := f.fail(); err != nil {
+		return &fs.PathError{Op: "write", Path: name, Err: err}
+	}
+	return os.WriteFile(name, data, perm)
+}
+
+func (f *flakyFileSystem) Rename(oldPath, newPath string) error {
+	if err := f.fail(); err != nil {
+		return &os.LinkError{Op: "rename", Old: oldPath, New: newPath, Err: err}
+	}
+	return os.Rename(oldPath, newPath)
+}
+
+func TestRetries(t *testing.T) {
+	previous := stdout
+	stdout = io.Discard
+	previousDelay := retryDelay
+	retryDelay = 0
+	defer func() {
+		stdout = previous
+		retryDelay = previousDelay
+		fileOps = osFileSystem{}
+		opts = options{}
+		globalRenames = make(map[string]string)
+	}()
+
+	tmpDir := t.TempDir()
+
+	globalRenames = map[string]string{"Button": "button"}
+	opts = options{retries: 3}
+
+	t.Run("transient write succeeds on retry", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "Card.vue")
+		if err := os.WriteFile(path, []byte(`import Button from './Button.vue'`), 0644); err != nil {
+			t.Fatalf("Failed to write Card.vue: %v", err)
+		}
+		flaky := &flakyFileSystem{failures: 2, err: syscall.EBUSY}
+		fileOps = flaky
+
+		if err := updateFileContent(path); err != nil {
+			t.Fatalf("updateFileContent failed: %v", err)
+		}
+		if flaky.calls != 3 {
+			t.Errorf("write attempted %d time(s); want 3", flaky.calls)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read Card.vue: %v", err)
+		}
+		if want := `import Button from './button.vue'`; string(content) != want {
+			t.Errorf("Card.vue = %q; want %q", content, want)
+		}
+	})
+
+	t.Run("rename gives up after the retries", func(t *testing.T) {
+		oldPath := filepath.Join(tmpDir, "Button.vue")
+		if err := os.WriteFile(oldPath, []byte(`export default {}`), 0644); err != nil {
+			t.Fatalf("Failed to write Button.vue: %v", err)
+		}
+		flaky := &flakyFileSystem{failures: 10, err: syscall.EAGAIN}
+		fileOps = flaky
+
+		err := renamePath(oldPath, filepath.Join(tmpDir, "button.vue"))
+		if !errors.Is(err, syscall.EAGAIN) {
+			t.Errorf("renamePath error = %v; want EAGAIN", err)
+		}
+		if flaky.calls != 4 {
+			t.Errorf("rename attempted %d time(s); want 4", flaky.calls)
+		}
+		if _, err := os.Stat(oldPath); err != nil {
+			t.Errorf("Button.vue is gone after a failed rename: %v", err)
+		}
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		flaky := &flakyFileSystem{failures: 1, err: syscall.EACCES}
+		fileOps = flaky
+
+		err := renamePath(filepath.Join(tmpDir, "Button.vue"), filepath.Join(tmpDir, "button.vue"))
+		if !errors.Is(err, syscall.EACCES) {
+			t.Errorf("renamePath error = %v; want EACCES", err)
+		}
+		if flaky.calls != 1 {
+			t.Errorf("rename attempted %d time(s); want 1", flaky.calls)
+		}
+	})
+}
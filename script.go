@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var renameScript []string
+
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./@~+") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func emitRename(oldPath, newPath string) {
+	renameScript = append(renameScript, fmt.Sprintf("git mv %s %s", shellQuote(oldPath), shellQuote(newPath)))
+}
+
+func writeRenameScript(w io.Writer, lines []string) {
+	fmt.Fprintln(w, "#!/bin/sh")
+	fmt.Fprintln(w, "set -e")
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
+
+// writeRenameScriptFile is --imports-only: the git mv script goes to a file
+// that can be run later, so the renames land in a commit of their own.
+func writeRenameScriptFile(path string, lines []string) error {
+	var buf bytes.Buffer
+	writeRenameScript(&buf, lines)
+	return os.WriteFile(path, buf.Bytes(), 0755)
+}
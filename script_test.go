@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmitScriptInsteadOfRenaming(t *testing.T) {
+	files := map[string]string{
+		"Card.vue":          `import DialogContent from './Dialog/DialogContent.vue'`,
+		"Dialog/Dialog.vue": `export default {}`,
+		"Dialog/DialogContent.vue": `import Dialog from './Dialog.vue'
+export default {}`,
+	}
+	tmpDir := writeTree(t, files)
+
+	opts = options{emitScript: true}
+	renameScript = nil
+	defer func() {
+		opts = options{}
+		renameScript = nil
+	}()
+
+	globalRenames = map[string]string{
+		"Dialog":        "dialog",
+		"DialogContent": "dialog-content",
+	}
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	for path := range files {
+		if _, err := os.Stat(filepath.Join(tmpDir, path)); err != nil {
+			t.Errorf("%s should not be renamed in --emit-script mode: %v", path, err)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "Dialog", "DialogContent.vue"))
+	if err != nil {
+		t.Fatalf("Failed to read DialogContent.vue: %v", err)
+	}
+	if string(content) != "import Dialog from './dialog.vue'\nexport default {}" {
+		t.Errorf("imports should still be rewritten in place:\n%s", content)
+	}
+
+	dialogDir := filepath.Join(tmpDir, "Dialog")
+	var buf bytes.Buffer
+	writeRenameScript(&buf, renameScript)
+	expected := "#!/bin/sh\nset -e\n" +
+		"git mv " + filepath.Join(dialogDir, "Dialog.vue") + " " + filepath.Join(dialogDir, "dialog.vue") + "\n" +
+		"git mv " + filepath.Join(dialogDir, "DialogContent.vue") + " " + filepath.Join(dialogDir, "dialog-content.vue") + "\n" +
+		"git mv " + dialogDir + " " + filepath.Join(tmpDir, "dialog") + "\n"
+	if buf.String() != expected {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s", expected, buf.String())
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"src/components/ui/Button.vue": "src/components/ui/Button.vue",
+		"my components/Button.vue":     "'my components/Button.vue'",
+		"it's/Button.vue":              `'it'\''s/Button.vue'`,
+	}
+	for input, expected := range tests {
+		if result := shellQuote(input); result != expected {
+			t.Errorf("shellQuote(%q) = %q; want %q", input, result, expected)
+		}
+	}
+}
+
+func TestImportsOnlyScriptMatchesRenameMap(t *testing.T) {
+	files := map[string]string{
+		"App.vue": `import Button from './Button.vue'
+import CardTitle from './Card/CardTitle.vue'`,
+		"Button.vue":         `export default {}`,
+		"Card/Card.vue":      `export default {}`,
+		"Card/CardTitle.vue": `export default {}`,
+	}
+	tmpDir := writeTree(t, files)
+
+	scriptPath := filepath.Join(tmpDir, "renames.sh")
+	opts = options{emitScript: true, importsOnly: scriptPath}
+	renameScript = nil
+	defer func() {
+		opts = options{}
+		renameScript = nil
+	}()
+
+	globalRenames = map[string]string{"Button": "button", "Card": "card", "CardTitle": "card-title"}
+	defer func() { globalRenames = make(map[string]string) }()
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+	if err := writeRenameScriptFile(scriptPath, renameScript); err != nil {
+		t.Fatalf("writeRenameScriptFile failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "App.vue"))
+	if err != nil {
+		t.Fatalf("Failed to read App.vue: %v", err)
+	}
+	if string(content) != "import Button from './button.vue'\nimport CardTitle from './card/card-title.vue'" {
+		t.Errorf("imports should be rewritten in place:\n%s", content)
+	}
+
+	script, err := os.ReadFile(scriptPath)
+	if err != nil {
+		t.Fatalf("Failed to read script: %v", err)
+	}
+	renamed := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(script)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 4 || fields[0] != "git" || fields[1] != "mv" {
+			continue
+		}
+		from, to := fields[2], fields[3]
+		if filepath.Dir(from) != filepath.Dir(to) {
+			t.Errorf("%s moves to another directory: %s", from, to)
+		}
+		oldName := strings.TrimSuffix(filepath.Base(from), ".vue")
+		newName := strings.TrimSuffix(filepath.Base(to), ".vue")
+		renamed[oldName] = newName
+	}
+	if len(renamed) != len(globalRenames) {
+		t.Errorf("script renames %v; want %v", renamed, globalRenames)
+	}
+	for oldName, newName := range globalRenames {
+		if renamed[oldName] != newName {
+			t.Errorf("script renames %s to %q; want %q", oldName, renamed[oldName], newName)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// customBlockSuffixes are the sibling files SFC custom blocks commonly load,
+// e.g. <docs src="./Button.md"> or <i18n src="./Button.json">. With
+// --sfc-blocks they are renamed along with their component and the block's
+// src is rewritten to match.
+var customBlockSuffixes = []string{".md", ".json", ".yaml", ".yml"}
+
+// customBlockRegex matches the opening tag of a top-level SFC block. Blocks
+// start at the beginning of a line, which keeps elements nested inside
+// <template> out of it.
+var (
+	customBlockRegex = regexp.MustCompile(`(?m)^<([a-zA-Z][\w-]*)\b[^>]*>`)
+	blockSrcRegex    = regexp.MustCompile(`(\ssrc=["']\./)([^"'/]+)(["'])`)
+)
+
+func rewriteCustomBlocks(filePath, content string) string {
+	if !opts.sfcBlocks || filepath.Ext(filePath) != ".vue" {
+		return content
+	}
+	return replaceOutsideComments(customBlockRegex, content, func(tag string) string {
+		switch customBlockRegex.FindStringSubmatch(tag)[1] {
+		case "template", "script", "style":
+			return tag
+		}
+		return blockSrcRegex.ReplaceAllStringFunc(tag, func(attr string) string {
+			match := blockSrcRegex.FindStringSubmatch(attr)
+			for _, suffix := range customBlockSuffixes {
+				name, ok := strings.CutSuffix(match[2], suffix)
+				if !ok {
+					continue
+				}
+				if newName, ok := globalRenames[name]; ok {
+					return match[1] + newName + suffix + match[3]
+				}
+			}
+			return attr
+		})
+	})
+}
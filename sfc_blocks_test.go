@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCustomBlockSources(t *testing.T) {
+	opts = options{sfcBlocks: true}
+	defer func() { opts = options{} }()
+	originalSuffixes := siblingSuffixes
+	siblingSuffixes = append(siblingSuffixes, customBlockSuffixes...)
+	defer func() { siblingSuffixes = originalSuffixes }()
+
+	files := map[string]string{
+		"Button.vue": `<template>
+  <a href="./Button.md">Docs</a>
+</template>
+
+<docs src="./Button.md" />
+<i18n lang="json" src='./Button.json'></i18n>
+<docs src="./Other.md" />`,
+		"Button.md":   "# Button",
+		"Button.json": "{}",
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = map[string]string{"Button": "button"}
+	defer func() { globalRenames = make(map[string]string) }()
+	if err := processFiles(tmpDir); err != nil {
+		t.Fatalf("processFiles failed: %v", err)
+	}
+
+	expected := `<template>
+  <a href="./Button.md">Docs</a>
+</template>
+
+<docs src="./button.md" />
+<i18n lang="json" src='./button.json'></i18n>
+<docs src="./Other.md" />`
+	content, err := os.ReadFile(filepath.Join(tmpDir, "button.vue"))
+	if err != nil {
+		t.Fatalf("Failed to read button.vue: %v", err)
+	}
+	if string(content) != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, content)
+	}
+	for _, name := range []string{"button.md", "button.json"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, name)); err != nil {
+			t.Errorf("expected %s to exist: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed canonical_components.txt
+var canonicalComponentList string
+
+// canonicalComponents is the set --strict-pascal accepts: the shadcn-vue
+// components and their documented sub-components, so a name that merely
+// starts with a prefix (ButtonishThing) is not renamed.
+var canonicalComponents = parseComponentList(canonicalComponentList)
+
+func parseComponentList(list string) map[string]bool {
+	names := make(map[string]bool)
+	for _, line := range strings.Split(list, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			names[line] = true
+		}
+	}
+	return names
+}
+
+// isCanonicalComponent also accepts names under a --prefix given for the
+// run, since those are the user's own components.
+func isCanonicalComponent(name string) bool {
+	if canonicalComponents[name] {
+		return true
+	}
+	for _, prefix := range opts.prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Component files shared between packages are sometimes symlinks. Renaming
+// one with os.Rename only moves the link, and rewriting through it resolves
+// relative imports from the wrong folder, so by default only the link is
+// renamed and the file it points at is left alone with a warning.
+// --follow-symlinks rewrites and renames that file as well.
+func isSymlink(path string) bool {
+	info, err := os.Lstat(path)
+	return err == nil && info.Mode()&os.ModeSymlink != 0
+}
+
+// followSymlink returns the file to read and write for path: path itself, or
+// the file a symlink resolves to under --follow-symlinks, so its relative
+// imports are resolved from where it really lives. It reports false, with a
+// warning, for a symlink that is not being followed.
+func followSymlink(path string) (string, bool) {
+	if !isSymlink(path) {
+		return path, true
+	}
+	if opts.followSymlinks {
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return path, true
+		}
+		return resolved, true
+	}
+
+	logf(path, "Warning: not rewriting symlink %s; the file it points at is left as it is (use --follow-symlinks to update it)\n", displayPath(path))
+	return path, false
+}
+
+// renameSymlinkedComponent renames a component file that is a symlink along
+// with the file it points at, when that carries the same name, and points the
+// renamed link at the renamed file. A target that an earlier link or the
+// tree's own walk already renamed is only linked to.
+func renameSymlinkedComponent(oldPath, newPath string) error {
+	target, err := os.Readlink(oldPath)
+	if err != nil {
+		return err
+	}
+	if filepath.Base(target) != filepath.Base(oldPath) {
+		return renamePath(oldPath, newPath)
+	}
+
+	resolved := target
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(filepath.Dir(oldPath), target)
+	}
+	newBase := filepath.Base(newPath)
+	if _, err := os.Lstat(resolved); err == nil {
+		if err := renamePath(resolved, filepath.Join(filepath.Dir(resolved), newBase)); err != nil {
+			return err
+		}
+	}
+	if err := renamePath(oldPath, newPath); err != nil {
+		return err
+	}
+	if opts.emitScript {
+		return nil
+	}
+	if err := os.Remove(newPath); err != nil {
+		return err
+	}
+	return os.Symlink(filepath.Join(filepath.Dir(target), newBase), newPath)
+}
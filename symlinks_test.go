@@ -0,0 +1,92 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSymlinkedComponent(t *testing.T) {
+	previous := stdout
+	defer func() {
+		stdout = previous
+		opts = options{}
+		globalRenames = make(map[string]string)
+	}()
+
+	tests := []struct {
+		name        string
+		follow      bool
+		wantLink    string
+		wantShared  string
+		wantContent string
+	}{
+		{
+			name:        "link renamed, target left alone",
+			wantLink:    filepath.Join("..", "shared", "Button.vue"),
+			wantShared:  "Button.vue",
+			wantContent: `import Card from '@/components/ui/Card.vue'`,
+		},
+		{
+			name:        "follow symlinks",
+			follow:      true,
+			wantLink:    filepath.Join("..", "shared", "button.vue"),
+			wantShared:  "button.vue",
+			wantContent: `import Card from '@/components/ui/card.vue'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+
+			ui := filepath.Join(tmpDir, "ui")
+			shared := filepath.Join(tmpDir, "shared")
+			for _, dir := range []string{ui, shared} {
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					t.Fatalf("Failed to create directory %s: %v", dir, err)
+				}
+			}
+			if err := os.WriteFile(filepath.Join(shared, "Button.vue"), []byte(`import Card from '@/components/ui/Card.vue'`), 0644); err != nil {
+				t.Fatalf("Failed to write shared component: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(ui, "Card.vue"), []byte(`import Button from './Button.vue'`), 0644); err != nil {
+				t.Fatalf("Failed to write Card.vue: %v", err)
+			}
+			if err := os.Symlink(filepath.Join("..", "shared", "Button.vue"), filepath.Join(ui, "Button.vue")); err != nil {
+				t.Skipf("Symlinks not supported: %v", err)
+			}
+
+			stdout = io.Discard
+			opts = options{followSymlinks: tt.follow}
+			globalRenames = map[string]string{"Button": "button", "Card": "card"}
+			if err := processFiles(ui); err != nil {
+				t.Fatalf("processFiles failed: %v", err)
+			}
+
+			link := filepath.Join(ui, "button.vue")
+			target, err := os.Readlink(link)
+			if err != nil {
+				t.Fatalf("Failed to read link %s: %v", link, err)
+			}
+			if target != tt.wantLink {
+				t.Errorf("link points at %s; want %s", target, tt.wantLink)
+			}
+			content, err := os.ReadFile(filepath.Join(shared, tt.wantShared))
+			if err != nil {
+				t.Fatalf("Failed to read shared component: %v", err)
+			}
+			if string(content) != tt.wantContent {
+				t.Errorf("shared component = %q; want %q", content, tt.wantContent)
+			}
+			content, err = os.ReadFile(filepath.Join(ui, "card.vue"))
+			if err != nil {
+				t.Fatalf("Failed to read card.vue: %v", err)
+			}
+			if want := `import Button from './button.vue'`; string(content) != want {
+				t.Errorf("card.vue = %q; want %q", content, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// nativeElements are HTML element names a kebab-case component tag could
+// collide with: <Table> written as <table> renders the native element, not
+// the component.
+var nativeElements = map[string]bool{
+	"a": true, "abbr": true, "address": true, "area": true, "article": true, "aside": true, "audio": true,
+	"b": true, "base": true, "bdi": true, "bdo": true, "blockquote": true, "body": true, "br": true, "button": true,
+	"canvas": true, "caption": true, "cite": true, "code": true, "col": true, "colgroup": true,
+	"data": true, "datalist": true, "dd": true, "del": true, "details": true, "dfn": true, "dialog": true, "div": true, "dl": true, "dt": true,
+	"em": true, "embed": true, "fieldset": true, "figcaption": true, "figure": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "head": true, "header": true, "hgroup": true, "hr": true, "html": true,
+	"i": true, "iframe": true, "img": true, "input": true, "ins": true, "kbd": true, "label": true, "legend": true, "li": true, "link": true,
+	"main": true, "map": true, "mark": true, "menu": true, "meta": true, "meter": true, "nav": true, "noscript": true,
+	"object": true, "ol": true, "optgroup": true, "option": true, "output": true, "p": true, "picture": true, "pre": true, "progress": true, "q": true,
+	"rp": true, "rt": true, "ruby": true, "s": true, "samp": true, "script": true, "search": true, "section": true, "select": true, "slot": true,
+	"small": true, "source": true, "span": true, "strong": true, "style": true, "sub": true, "summary": true, "sup": true,
+	"table": true, "tbody": true, "td": true, "template": true, "textarea": true, "tfoot": true, "th": true, "thead": true, "time": true,
+	"title": true, "tr": true, "track": true, "u": true, "ul": true, "var": true, "video": true, "wbr": true,
+}
+
+var componentTagRegex = regexp.MustCompile(`(</?)([A-Z][A-Za-z0-9]*)\b`)
+
+// rewriteComponentTags is --rewrite-tags: PascalCase component tags in a
+// .vue file's <template> are written in kebab-case, <DialogContent> becoming
+// <dialog-content>. A tag whose kebab-case form is a native element is kept
+// unless --allow-native-tag-collision is given.
+func rewriteComponentTags(filePath, content string) string {
+	if !opts.rewriteTags || !strings.HasSuffix(filePath, ".vue") {
+		return content
+	}
+	start := strings.Index(content, "<template")
+	end := strings.LastIndex(content, "</template>")
+	if start < 0 || end < start {
+		return content
+	}
+
+	template := componentTagRegex.ReplaceAllStringFunc(content[start:end], func(tag string) string {
+		match := componentTagRegex.FindStringSubmatch(tag)
+		name := match[2]
+		if _, ok := globalRenames[name]; !ok {
+			return tag
+		}
+		kebab := toKebabCase(name)
+		if nativeElements[kebab] && !opts.allowNativeTagCollision {
+			if match[1] == "<" {
+				logf(filePath, "Keeping <%s> in %s: <%s> is a native HTML element\n", name, displayPath(filePath), kebab)
+			}
+			return tag
+		}
+		return match[1] + kebab
+	})
+	return content[:start] + template + content[end:]
+}
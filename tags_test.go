@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRewriteComponentTags(t *testing.T) {
+	previous := stdout
+	var out bytes.Buffer
+	stdout = &out
+	defer func() { stdout = previous }()
+
+	globalRenames = map[string]string{
+		"DialogContent": "dialog-content",
+		"Table":         "table",
+		"Input":         "input",
+		"Label":         "label",
+		"Select":        "select",
+	}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	input := `<script setup lang="ts">
+import { Table } from '@/components/ui/table'
+</script>
+
+<template>
+  <DialogContent>
+    <Table><TableRow /></Table>
+    <Label for="name">Name</Label>
+    <Input id="name" />
+    <Select v-model="value" />
+  </DialogContent>
+</template>`
+
+	tests := []struct {
+		name     string
+		allow    bool
+		expected string
+	}{
+		{
+			name: "native collisions kept",
+			expected: `<script setup lang="ts">
+import { Table } from '@/components/ui/table'
+</script>
+
+<template>
+  <dialog-content>
+    <Table><TableRow /></Table>
+    <Label for="name">Name</Label>
+    <Input id="name" />
+    <Select v-model="value" />
+  </dialog-content>
+</template>`,
+		},
+		{
+			name:  "native collisions allowed",
+			allow: true,
+			expected: `<script setup lang="ts">
+import { Table } from '@/components/ui/table'
+</script>
+
+<template>
+  <dialog-content>
+    <table><TableRow /></table>
+    <label for="name">Name</label>
+    <input id="name" />
+    <select v-model="value" />
+  </dialog-content>
+</template>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts = options{rewriteTags: true, allowNativeTagCollision: tt.allow}
+			defer func() { opts = options{} }()
+			out.Reset()
+
+			if result := rewriteComponentTags("App.vue", input); result != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, result)
+			}
+			for _, name := range []string{"Table", "Input", "Label", "Select"} {
+				logged := strings.Contains(out.String(), "Keeping <"+name+">")
+				if logged == tt.allow {
+					t.Errorf("logged collision for %s = %v; want %v", name, logged, !tt.allow)
+				}
+			}
+		})
+	}
+
+	opts = options{}
+	if result := rewriteComponentTags("App.vue", input); result != input {
+		t.Errorf("tags rewritten without --rewrite-tags:\n%s", result)
+	}
+}
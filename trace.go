@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// With --trace-file, every file read and write, every isPascalCase decision
+// and every pattern tried is written to the trace, for attaching to bug
+// reports. tracef returns at once when no trace is open, so the default path
+// only pays for a nil check.
+var (
+	traceOut  *bufio.Writer
+	traceFile *os.File
+	traceMu   sync.Mutex
+)
+
+func openTrace(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating trace file: %v", err)
+	}
+	traceFile = file
+	traceOut = bufio.NewWriter(file)
+	return nil
+}
+
+func closeTrace() {
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	if traceOut == nil {
+		return
+	}
+	traceOut.Flush()
+	traceFile.Close()
+	traceOut, traceFile = nil, nil
+}
+
+func tracing() bool {
+	return traceOut != nil
+}
+
+func tracef(format string, args ...any) {
+	if traceOut == nil {
+		return
+	}
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	fmt.Fprintf(traceOut, format+"\n", args...)
+}
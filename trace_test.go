@@ -0,0 +1,59 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTraceFile(t *testing.T) {
+	previous := stdout
+	stdout = io.Discard
+	defer func() { stdout = previous }()
+
+	tmpDir := t.TempDir()
+
+	filePath := filepath.Join(tmpDir, "App.vue")
+	if err := os.WriteFile(filePath, []byte(`import Button from './Button.vue'`), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	// Without a trace open, tracing is a no-op.
+	tracef("not written %d", 1)
+
+	tracePath := filepath.Join(tmpDir, "trace.log")
+	if err := openTrace(tracePath); err != nil {
+		t.Fatalf("openTrace failed: %v", err)
+	}
+	globalRenames = map[string]string{"Button": "button"}
+	defer func() { globalRenames = make(map[string]string) }()
+	isPascalCase("ButtonGroup")
+	if err := updateFileContent(filePath); err != nil {
+		t.Fatalf("updateFileContent failed: %v", err)
+	}
+	closeTrace()
+
+	content, err := os.ReadFile(tracePath)
+	if err != nil {
+		t.Fatalf("Failed to read trace: %v", err)
+	}
+	trace := string(content)
+	for _, want := range []string{
+		"read " + filePath,
+		`isPascalCase "ButtonGroup" = true`,
+		`pattern ` + filePath + `: string "from './Button.vue'"`,
+		"write " + filePath,
+	} {
+		if !strings.Contains(trace, want) {
+			t.Errorf("trace missing %q:\n%s", want, trace)
+		}
+	}
+	if strings.Contains(trace, "not written") {
+		t.Errorf("trace has a line written before it was opened:\n%s", trace)
+	}
+	if tracing() {
+		t.Errorf("tracing() = true after closeTrace")
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// printTree draws the components directory as it is now, with each entry the
+// rename map will change shown as "Old -> new". Plain box-drawing characters
+// are used so it renders the same in any terminal or log.
+func printTree(w io.Writer, root string) error {
+	fmt.Fprintf(w, "%s/\n", filepath.Base(root))
+	return printTreeEntries(w, root, root, "")
+}
+
+func printTreeEntries(w io.Writer, root, dir, indent string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var shown []os.DirEntry
+	for _, entry := range entries {
+		if !isToolFile(entry.Name()) {
+			shown = append(shown, entry)
+		}
+	}
+
+	for i, entry := range shown {
+		branch, nested := "├── ", "│   "
+		if i == len(shown)-1 {
+			branch, nested = "└── ", "    "
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		name, planned := entry.Name(), filepath.Base(plannedPath(root, path))
+		if entry.IsDir() {
+			if newName, ok := globalRenames[name]; ok && fileFilter == nil {
+				planned = newName
+			}
+			name, planned = name+"/", planned+"/"
+		}
+
+		if planned == name {
+			fmt.Fprintf(w, "%s%s%s\n", indent, branch, name)
+		} else {
+			fmt.Fprintf(w, "%s%s%s -> %s\n", indent, branch, name, planned)
+		}
+
+		if entry.IsDir() {
+			if err := printTreeEntries(w, root, path, indent+nested); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
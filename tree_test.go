@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPrintTree(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, path := range []string{
+		"Dialog/Dialog.vue",
+		"Dialog/DialogContent.vue",
+		"Dialog/index.ts",
+		"Button.vue",
+		"Button.stories.ts",
+		"utils.ts",
+	} {
+		fullPath := filepath.Join(tmpDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", path, err)
+		}
+		if err := os.WriteFile(fullPath, nil, 0644); err != nil {
+			t.Fatalf("Failed to write file %s: %v", path, err)
+		}
+	}
+
+	globalRenames = map[string]string{"Dialog": "dialog", "DialogContent": "dialog-content", "Button": "button"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	var output bytes.Buffer
+	if err := printTree(&output, tmpDir); err != nil {
+		t.Fatalf("printTree failed: %v", err)
+	}
+
+	expected := filepath.Base(tmpDir) + `/
+├── Button.stories.ts -> button.stories.ts
+├── Button.vue -> button.vue
+├── Dialog/ -> dialog/
+│   ├── Dialog.vue -> dialog.vue
+│   ├── DialogContent.vue -> dialog-content.vue
+│   └── index.ts
+└── utils.ts
+`
+	if output.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, output.String())
+	}
+}
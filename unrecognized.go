@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// pascalSegmentRegex matches a path segment spelled like a component: a
+// capital first letter and at least one lowercase letter, so constants such
+// as ICONS are not reported.
+var pascalSegmentRegex = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*[a-z][a-zA-Z0-9]*$`)
+
+// unrecognizedNames maps each PascalCase name seen in a local import path
+// that is not renamed because it starts with no known prefix to the first
+// file it was seen in.
+var unrecognizedNames = make(map[string]string)
+
+// noteUnrecognizedNames records the PascalCase segments of content's local
+// import specifiers that start with no component prefix. Package imports
+// are ignored, as their PascalCase names (icon sets and the like) are not
+// the project's components.
+func noteUnrecognizedNames(filePath, content string) {
+	for _, specifier := range importSpecifiers(stripComments(content)) {
+		if !isLocalSpecifier(specifier) {
+			continue
+		}
+		for _, segment := range strings.Split(specifier, "/") {
+			name := strings.TrimSuffix(segment, path.Ext(segment))
+			if !pascalSegmentRegex.MatchString(name) || hasComponentPrefix(name) || isNonComponentName(name) {
+				continue
+			}
+			if _, seen := unrecognizedNames[name]; !seen {
+				unrecognizedNames[name] = filePath
+			}
+		}
+	}
+}
+
+func isLocalSpecifier(specifier string) bool {
+	if isRelativeSpecifier(specifier) {
+		return true
+	}
+	for _, alias := range append([]string{"@", "~", "@@", "~~"}, uiAliases...) {
+		if strings.HasPrefix(specifier, alias+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// printUnrecognizedNames prints, once per run, the names noteUnrecognizedNames
+// recorded that nothing else detected, with a hint on how to include them.
+func printUnrecognizedNames(w io.Writer) {
+	var names []string
+	for name := range unrecognizedNames {
+		if _, renamed := globalRenames[name]; !renamed {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(w, "\nNote: %d PascalCase name(s) in import paths start with no known component prefix and were left alone:\n", len(names))
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s (first seen in %s)\n", name, displayPath(unrecognizedNames[name]))
+	}
+	fmt.Fprintf(w, "If these are your own components, rerun with --prefix for each, e.g. --prefix %s\n", names[0])
+}
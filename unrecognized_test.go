@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnrecognizedNames(t *testing.T) {
+	previous := stdout
+	stdout = io.Discard
+	defer func() {
+		stdout = previous
+		globalRenames = make(map[string]string)
+		unrecognizedNames = make(map[string]string)
+	}()
+
+	tmpDir := t.TempDir()
+
+	content := `<script setup>
+import FancyWidget from './FancyWidget.vue'
+import { Button } from '@/components/ui/Button'
+import { ChevronDown } from 'lucide-vue-next'
+import { ButtonProps } from './ButtonProps'
+</script>`
+	if err := os.WriteFile(filepath.Join(tmpDir, "App.vue"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write App.vue: %v", err)
+	}
+
+	globalRenames = make(map[string]string)
+	unrecognizedNames = make(map[string]string)
+	if err := buildRenameMap(tmpDir); err != nil {
+		t.Fatalf("buildRenameMap failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	printUnrecognizedNames(&out)
+	summary := out.String()
+	if !strings.Contains(summary, "FancyWidget (first seen in") || !strings.Contains(summary, "--prefix FancyWidget") {
+		t.Errorf("summary does not suggest --prefix for FancyWidget:\n%s", summary)
+	}
+	for _, name := range []string{"Button ", "ChevronDown", "ButtonProps"} {
+		if strings.Contains(summary, name) {
+			t.Errorf("summary lists %q, which is detected or not a local component:\n%s", name, summary)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	renderCallRegex = regexp.MustCompile(`\bh\(\s*([A-Z][A-Za-z0-9]*)\b`)
+	jsxTagRegex     = regexp.MustCompile(`<([A-Z][A-Za-z0-9]*)[\s/>]`)
+)
+
+func isJSXFile(name string) bool {
+	ext := filepath.Ext(name)
+	return ext == ".tsx" || ext == ".jsx"
+}
+
+// findComponentUsages lists the renamed components a file uses by
+// identifier: in h(Button, ...) render calls, and as <Button /> tags in TSX
+// and JSX. Comments are skipped.
+func findComponentUsages(filePath, content string) []string {
+	regexes := []*regexp.Regexp{renderCallRegex}
+	if isJSXFile(filePath) {
+		regexes = append(regexes, jsxTagRegex)
+	}
+
+	spans := commentSpans(content)
+	found := make(map[string]bool)
+	var names []string
+	for _, re := range regexes {
+		for _, match := range re.FindAllStringSubmatchIndex(content, -1) {
+			name := content[match[2]:match[3]]
+			if _, ok := globalRenames[name]; !ok || found[name] || inComment(spans, match[0]) {
+				continue
+			}
+			found[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// printUsageReport is --usage: for each renamed component, the files that
+// use it in a render function or a TSX tag. The identifiers keep their names,
+// so nothing here is rewritten; it shows which usage sites depend on the
+// renamed files resolving, e.g. through auto-imports. .tsx and .jsx files
+// are scanned for the report even when they are not rewritten.
+func printUsageReport(w io.Writer, dir string) error {
+	usages := make(map[string][]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !(isSourceFile(d.Name()) || isJSXFile(d.Name())) || !inFileFilter(path) {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, name := range findComponentUsages(path, string(content)) {
+			usages[name] = append(usages[name], displayPath(path))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "\nRender function and TSX usage:")
+	if len(usages) == 0 {
+		fmt.Fprintln(w, "  none")
+		return nil
+	}
+	names := make([]string, 0, len(usages))
+	for name := range usages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s (%s): %s\n", name, globalRenames[name], strings.Join(usages[name], ", "))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestRenderFunctionUsage(t *testing.T) {
+	globalRenames = map[string]string{"Button": "button", "DialogContent": "dialog-content"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	renderFn := `import { h } from 'vue'
+import Button from '@/components/ui/Button/Button.vue'
+import { DialogContent } from '@/components/ui/Dialog'
+
+export default {
+  render() {
+    // h(Card) is commented out
+    return h(DialogContent, null, () => h( Button, { size: 'sm' }, 'Save'))
+  },
+}`
+	if result := findComponentUsages("Panel.ts", renderFn); !slices.Equal(result, []string{"DialogContent", "Button"}) {
+		t.Errorf("findComponentUsages() = %v; want [DialogContent Button]", result)
+	}
+
+	tsx := `export const Save = () => <Button size="sm">Save</Button>`
+	if result := findComponentUsages("Save.tsx", tsx); !slices.Equal(result, []string{"Button"}) {
+		t.Errorf("findComponentUsages() in TSX = %v; want [Button]", result)
+	}
+	if result := findComponentUsages("Save.ts", tsx); len(result) != 0 {
+		t.Errorf("findComponentUsages() took tags in a .ts file: %v", result)
+	}
+
+	opts.usage = true
+	defer func() { opts.usage = false }()
+	tmpDir := writeTree(t, map[string]string{"Panel.ts": renderFn, "Save.tsx": tsx})
+
+	// --usage only adds the report; .tsx files are still not rewritten.
+	if isSourceFile("Save.tsx") {
+		t.Errorf("isSourceFile(Save.tsx) = true under --usage; want the rewrite set unchanged")
+	}
+
+	var out bytes.Buffer
+	if err := printUsageReport(&out, tmpDir); err != nil {
+		t.Fatalf("printUsageReport failed: %v", err)
+	}
+	panel := filepath.Join(tmpDir, "Panel.ts")
+	save := filepath.Join(tmpDir, "Save.tsx")
+	expected := "\nRender function and TSX usage:\n" +
+		"  Button (button): " + panel + ", " + save + "\n" +
+		"  DialogContent (dialog-content): " + panel + "\n"
+	if out.String() != expected {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s", expected, out.String())
+	}
+}
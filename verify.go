@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type unresolvedImport struct {
+	file      string
+	specifier string
+}
+
+var uiSpecifierRegex = regexp.MustCompile(`^` + aliasPattern + `components/ui/(.*)$`)
+
+var resolveExtensions = []string{"", ".vue", ".ts", ".js", ".tsx", ".jsx", ".d.ts"}
+
+func importSpecifiers(content string) []string {
+	var specifiers []string
+	spans := commentSpans(content)
+	for _, match := range importStatementRegex.FindAllStringIndex(content, -1) {
+		if inComment(spans, match[0]) {
+			continue
+		}
+		statement := content[match[0]:match[1]]
+		closing := len(statement) - 1
+		opening := strings.LastIndexByte(statement[:closing], statement[closing])
+		specifier, _ := splitSpecifierSuffix(statement[opening+1 : closing])
+		specifiers = append(specifiers, specifier)
+	}
+	return specifiers
+}
+
+// resolveSpecifier maps a relative or ui-aliased specifier in a file under
+// root to the path it points at. Ui-aliased specifiers are resolved against
+// the ui folder, which root may contain, and other @/components/ specifiers
+// against its parent. Package imports and other aliases are not resolved.
+func resolveSpecifier(root, filePath, specifier string) (string, bool) {
+	if isRelativeSpecifier(specifier) {
+		return filepath.Join(filepath.Dir(filePath), specifier), true
+	}
+	if _, rest := splitUISpecifier(specifier); rest != "" {
+		return filepath.Join(locateUIDir(root), rest), true
+	}
+	if match := componentsSpecifierRegex.FindStringSubmatch(specifier); match != nil {
+		return filepath.Join(locateComponentsDir(root), match[2]), true
+	}
+	return "", false
+}
+
+// configUIDir is the ui folder components.json points at, if any.
+var configUIDir string
+
+// locateUIDir finds the ui folder that @/components/ui/ and the ui aliases
+// point at, for a run on root: the components.json folder when it is under
+// root, else root itself when it is named ui, else its ui or components/ui
+// subfolder. It falls back to root. The result is spelled like root, so it
+// can be compared with paths walked from it.
+func locateUIDir(root string) string {
+	if configUIDir != "" {
+		absRoot, err := filepath.Abs(root)
+		if err == nil && isWithin(configUIDir, absRoot) {
+			if rel, err := filepath.Rel(absRoot, configUIDir); err == nil {
+				return filepath.Join(root, rel)
+			}
+		}
+	}
+	if filepath.Base(root) == "ui" {
+		return root
+	}
+	for _, sub := range []string{"ui", filepath.Join("components", "ui")} {
+		if info, err := os.Stat(filepath.Join(root, sub)); err == nil && info.IsDir() {
+			return filepath.Join(root, sub)
+		}
+	}
+	return root
+}
+
+// plannedPathWithin is plannedPath for paths under dir; anything else is not
+// renamed by the run.
+func plannedPathWithin(dir, path string) string {
+	if !isWithin(path, dir) {
+		return path
+	}
+	return plannedPath(dir, path)
+}
+
+func resolvesIn(files map[string]bool, path string) bool {
+	for _, ext := range resolveExtensions {
+		if files[path+ext] {
+			return true
+		}
+	}
+	for _, barrel := range barrelFileNames {
+		if files[filepath.Join(path, barrel)] {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyPlan simulates the run in memory: it builds the post-rename file set
+// and checks that every import which resolves today still resolves once its
+// statement has been rewritten and the files have moved, and that every
+// rewritten import resolved in the first place. Nothing is written.
+func verifyPlan(dir string) ([]unresolvedImport, error) {
+	previous := stdout
+	stdout = io.Discard
+	defer func() { stdout = previous }()
+
+	// With --scope the whole components root is simulated, so imports of
+	// components outside the scope resolve and files outside it that import
+	// a renamed component are caught.
+	root := dir
+	if scopeRoot != "" {
+		root = componentRoot
+		dir = scopeRoot
+	}
+
+	before := make(map[string]bool)
+	after := make(map[string]bool)
+	var sources []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		before[path] = true
+		after[plannedPathWithin(dir, path)] = true
+		if isSourceFile(d.Name()) {
+			sources = append(sources, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var unresolved []unresolvedImport
+	for _, path := range sources {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		original := string(content)
+		rewritten := original
+		if inFileFilter(path) && isWithin(path, dir) {
+			rewritten = rewriteContent(path, original)
+		}
+
+		newPath := plannedPathWithin(dir, path)
+		oldSpecifiers := importSpecifiers(original)
+		newSpecifiers := importSpecifiers(rewritten)
+		for i, oldSpecifier := range oldSpecifiers {
+			if i >= len(newSpecifiers) {
+				break
+			}
+			// A rewritten import that cannot be resolved today cannot be
+			// checked either, so it fails rather than passing unseen.
+			target, ok := resolveSpecifier(root, path, oldSpecifier)
+			if !ok || !resolvesIn(before, target) {
+				if newSpecifiers[i] != oldSpecifier {
+					unresolved = append(unresolved, unresolvedImport{file: newPath, specifier: newSpecifiers[i]})
+				}
+				continue
+			}
+			target, _ = resolveSpecifier(root, newPath, newSpecifiers[i])
+			if !resolvesIn(after, target) {
+				unresolved = append(unresolved, unresolvedImport{file: newPath, specifier: newSpecifiers[i]})
+			}
+		}
+	}
+
+	sort.Slice(unresolved, func(i, j int) bool {
+		if unresolved[i].file != unresolved[j].file {
+			return unresolved[i].file < unresolved[j].file
+		}
+		return unresolved[i].specifier < unresolved[j].specifier
+	})
+	return unresolved, nil
+}
+
+func printUnresolved(w io.Writer, unresolved []unresolvedImport) {
+	fmt.Fprintf(w, "\n%d import(s) would not resolve after the rename:\n", len(unresolved))
+	for _, u := range unresolved {
+		fmt.Fprintf(w, "  %s: %s\n", displayPath(u.file), u.specifier)
+	}
+}
+
+func printDryRun(dir string) error {
+	affected, err := affectedFiles(dir)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "\nDry run: %d file(s) would change:\n", len(affected))
+	for _, path := range affected {
+		if planned := plannedPath(dir, path); planned != path {
+			fmt.Fprintf(stdout, "  %s -> %s\n", displayPath(path), displayPath(planned))
+		} else {
+			fmt.Fprintf(stdout, "  %s\n", displayPath(path))
+		}
+	}
+	return nil
+}
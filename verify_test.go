@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyPlan(t *testing.T) {
+	files := map[string]string{
+		"Dialog/Dialog.vue":      `export default {}`,
+		"Dialog/DialogTitle.vue": `export default {}`,
+		"Dialog/index.ts":        `export { default as Dialog } from './Dialog.vue'`,
+		"App.vue": `import { Dialog } from '@/components/ui/Dialog'
+import DialogTitle from './Dialog/DialogTitle.vue'
+import { cn } from '@/lib/utils'`,
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = map[string]string{"Dialog": "dialog", "DialogTitle": "dialog-title"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	unresolved, err := verifyPlan(tmpDir)
+	if err != nil {
+		t.Fatalf("verifyPlan failed: %v", err)
+	}
+	if len(unresolved) != 0 {
+		t.Errorf("unresolved = %v; want none", unresolved)
+	}
+
+	// Limiting the run to DialogTitle.vue renames it but leaves App.vue's
+	// import of it as it was.
+	titlePath, err := filepath.EvalSymlinks(filepath.Join(tmpDir, "Dialog", "DialogTitle.vue"))
+	if err != nil {
+		t.Fatalf("Failed to resolve path: %v", err)
+	}
+	fileFilter = map[string]bool{titlePath: true}
+	defer func() { fileFilter = nil }()
+
+	unresolved, err = verifyPlan(tmpDir)
+	if err != nil {
+		t.Fatalf("verifyPlan failed: %v", err)
+	}
+	if len(unresolved) != 1 || unresolved[0].specifier != "./Dialog/DialogTitle.vue" {
+		t.Fatalf("unresolved = %v; want only ./Dialog/DialogTitle.vue", unresolved)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "Dialog", "DialogTitle.vue")); err != nil {
+		t.Errorf("verifyPlan touched disk: %v", err)
+	}
+}
+
+// TestVerifyPlanAboveUI runs on the folder that holds ui/, so @/components/ui
+// imports must be resolved against ui/ rather than the folder itself.
+func TestVerifyPlanAboveUI(t *testing.T) {
+	files := map[string]string{
+		"ui/Button/Button.vue": `export default {}`,
+		"ui/Button/index.ts":   `export { default as Button } from './Button.vue'`,
+		"App.vue":              `import { Button } from '@/components/ui/Button'`,
+	}
+	tmpDir := writeTree(t, files)
+
+	globalRenames = map[string]string{"Button": "button"}
+	defer func() { globalRenames = make(map[string]string) }()
+
+	unresolved, err := verifyPlan(tmpDir)
+	if err != nil {
+		t.Fatalf("verifyPlan failed: %v", err)
+	}
+	if len(unresolved) != 0 {
+		t.Errorf("unresolved = %v; want none", unresolved)
+	}
+
+	// Limiting the run to App.vue rewrites its import but leaves ui/Button
+	// where it is.
+	appPath, err := filepath.EvalSymlinks(filepath.Join(tmpDir, "App.vue"))
+	if err != nil {
+		t.Fatalf("Failed to resolve path: %v", err)
+	}
+	fileFilter = map[string]bool{appPath: true}
+	defer func() { fileFilter = nil }()
+
+	unresolved, err = verifyPlan(tmpDir)
+	if err != nil {
+		t.Fatalf("verifyPlan failed: %v", err)
+	}
+	if len(unresolved) != 1 || unresolved[0].specifier != "@/components/ui/button" {
+		t.Errorf("unresolved = %v; want only @/components/ui/button", unresolved)
+	}
+}